@@ -0,0 +1,227 @@
+package skipset
+
+import "slices"
+
+// The set algebra functions walk the level 0 of both skip sets at the same time, so they cost
+// O(n+m) instead of O(n*log(m)) as calling Contains for each value. The two skip sets must have
+// the same order, and the result has the order of a. They panic if the values of b are found out
+// of the order of a, e.g. Union(New[int](), NewDesc[int]()) with two or more values in each set.
+// Like Range, the walks are not a consistent view if the skip sets are being modified concurrently,
+// use Snapshot if it is required.
+
+// orderMismatch is the panic value of the set algebra functions if the skip sets have different orders.
+const orderMismatch = "skipset: the skip sets have different orders"
+
+// Union returns a new skip set that contains the values in a or b.
+func Union[T any](a, b *Set[T]) *Set[T] {
+	return combine(a, b, func(inA, inB bool) bool { return inA || inB })
+}
+
+// Intersect returns a new skip set that contains the values in both a and b.
+func Intersect[T any](a, b *Set[T]) *Set[T] {
+	return combine(a, b, func(inA, inB bool) bool { return inA && inB })
+}
+
+// Difference returns a new skip set that contains the values in a but not in b.
+func Difference[T any](a, b *Set[T]) *Set[T] {
+	return combine(a, b, func(inA, inB bool) bool { return inA && !inB })
+}
+
+// SymmetricDifference returns a new skip set that contains the values in either a or b but not both.
+func SymmetricDifference[T any](a, b *Set[T]) *Set[T] {
+	return combine(a, b, func(inA, inB bool) bool { return inA != inB })
+}
+
+// IntersectAll returns a new skip set that contains the values in all the sets, it has the order
+// of the sets which must be the same. It returns nil if no set is given. See RangeIntersection.
+func IntersectAll[T any](sets ...*Set[T]) *Set[T] {
+	if len(sets) == 0 {
+		return nil
+	}
+	var values []T
+	RangeIntersection(sets, func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return buildMerged(sets[0], values)
+}
+
+// RangeIntersection calls f sequentially for each value in all the sets, the sets must have the same order.
+// If f returns false, range stops the iteration. It panics if the first two values of a set are out
+// of the order of the other sets.
+//
+// It is a leapfrog join: the sets seek the current candidate value in turn, each seek starts from
+// the result of the previous one in the same set and skips the values less than the candidate via
+// the towers, then the result becomes the next candidate. So the small sets drive the skipping over
+// the large ones, and no intermediate result is built.
+func RangeIntersection[T any](sets []*Set[T], f func(value T) bool) {
+	switch len(sets) {
+	case 0:
+		return
+	case 1:
+		sets[0].Range(f)
+		return
+	}
+	for _, s := range sets[1:] {
+		checkOrder(sets[0], s)
+	}
+	// Start from the smallest set, its values are the first candidates.
+	sets = slices.Clone(sets)
+	slices.SortFunc(sets, func(a, b *Set[T]) int { return a.Len() - b.Len() })
+	seekers := make([]*seeker[T], len(sets))
+	for i, s := range sets {
+		seekers[i] = newSeeker(s)
+	}
+	less := sets[0].less
+	x := sets[0].findFirstValid(sets[0].load().header.atomicLoadNext(0))
+	owner := 0   // the index of the set that x belongs to
+	matched := 1 // the number of sets in turn that contain the candidate x.value
+	for i := 1; x != nil; i = (i + 1) % len(sets) {
+		if matched == len(sets) {
+			if !f(x.value) {
+				return
+			}
+			x = sets[owner].findFirstValid(x.atomicLoadNext(0))
+			if x == nil {
+				return
+			}
+			matched = 1
+		}
+		y := seekers[i].seek(x.value)
+		if y == nil {
+			return
+		}
+		if less(x.value, y.value) {
+			matched = 1
+		} else {
+			matched++
+		}
+		x, owner = y, i
+	}
+}
+
+// combine returns a new skip set that contains the values for which keep returns true,
+// it is built from the merged values in O(n) via buildSorted.
+func combine[T any](a, b *Set[T], keep func(inA, inB bool) bool) *Set[T] {
+	var values []T
+	merge(a, b, func(value T, inA, inB bool) bool {
+		if keep(inA, inB) {
+			values = append(values, value)
+		}
+		return true
+	})
+	return buildMerged(a, values)
+}
+
+// buildMerged returns a new skip set with the order of s that contains the sorted values, which are
+// merged from the skip sets with the same order. It panics if they are not sorted since the orders differ.
+func buildMerged[T any](s *Set[T], values []T) *Set[T] {
+	c, err := buildSorted(newSet(s.less, s.ordered), values)
+	if err != nil {
+		panic(orderMismatch)
+	}
+	return c
+}
+
+// checkOrder panics if the first two values of b are out of the order of a.
+func checkOrder[T any](a, b *Set[T]) {
+	if x := b.findFirstValid(b.load().header.atomicLoadNext(0)); x != nil {
+		if y := b.findFirstValid(x.atomicLoadNext(0)); y != nil && !a.less(x.value, y.value) {
+			panic(orderMismatch)
+		}
+	}
+}
+
+// checkOrders panics if the first two values of a are out of the order of b or vice versa,
+// which catches the different orders unless both skip sets have less than two values.
+func checkOrders[T any](a, b *Set[T]) {
+	checkOrder(a, b)
+	checkOrder(b, a)
+}
+
+// merge calls f sequentially for each value in a or b in the order of a, inA and inB report
+// whether the value is in a and b. If f returns false, merge stops the iteration.
+// It panics if two values of b are out of the order of a.
+func merge[T any](a, b *Set[T], f func(value T, inA, inB bool) bool) {
+	x := a.findFirstValid(a.load().header.atomicLoadNext(0))
+	y := b.findFirstValid(b.load().header.atomicLoadNext(0))
+	nextB := func() {
+		prev := y.value
+		if y = b.findFirstValid(y.atomicLoadNext(0)); y != nil && !a.less(prev, y.value) {
+			panic(orderMismatch)
+		}
+	}
+	for x != nil || y != nil {
+		var ok bool
+		switch {
+		case y == nil || (x != nil && a.less(x.value, y.value)):
+			ok = f(x.value, true, false)
+			x = a.findFirstValid(x.atomicLoadNext(0))
+		case x == nil || a.less(y.value, x.value):
+			ok = f(y.value, false, true)
+			nextB()
+		default:
+			ok = f(x.value, true, true)
+			x = a.findFirstValid(x.atomicLoadNext(0))
+			nextB()
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// UnionWith adds the values in other into the skip set, it returns the number of values added.
+// See AddBatch.
+func (s *Set[T]) UnionWith(other *Set[T]) int {
+	var values []T
+	other.Range(func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return s.AddBatch(values)
+}
+
+// IntersectWith removes the values not in other from the skip set, it returns the number of values removed.
+// See Retain. It panics before removing any value if the first two values of either skip set are out of
+// the order of the other one.
+func (s *Set[T]) IntersectWith(other *Set[T]) int {
+	checkOrders(s, other)
+	// The values are passed to the predicate in order, so the cursor in other only moves forward.
+	y := other.findFirstValid(other.load().header.atomicLoadNext(0))
+	return s.Retain(func(value T) bool {
+		for y != nil && s.less(y.value, value) {
+			y = other.findFirstValid(y.atomicLoadNext(0))
+		}
+		return y != nil && !s.less(value, y.value)
+	})
+}
+
+// DifferenceWith removes the values in other from the skip set, it returns the number of values removed.
+// See RemoveBatch.
+func (s *Set[T]) DifferenceWith(other *Set[T]) int {
+	var values []T
+	other.Range(func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return s.RemoveBatch(values)
+}
+
+// SymmetricDifferenceWith removes the values in both the skip set and other, then adds the values
+// only in other. It returns the number of values removed and added. The removal and the addition
+// are two batches, the concurrent readers may see the skip set between them.
+func (s *Set[T]) SymmetricDifferenceWith(other *Set[T]) (removed, added int) {
+	var toRemove, toAdd []T
+	merge(s, other, func(value T, inA, inB bool) bool {
+		if inB {
+			if inA {
+				toRemove = append(toRemove, value)
+			} else {
+				toAdd = append(toAdd, value)
+			}
+		}
+		return true
+	})
+	return s.RemoveBatch(toRemove), s.AddBatch(toAdd)
+}
@@ -0,0 +1,187 @@
+package skipset
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestSetAlgebra(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		a, b := NewInt64(), NewInt64()
+		inA, inB := make(map[int64]bool), make(map[int64]bool)
+		for j := 0; j < int(fastrand.Uint32n(500)); j++ {
+			v := int64(fastrand.Uint32n(1000))
+			a.Add(v)
+			inA[v] = true
+		}
+		for j := 0; j < int(fastrand.Uint32n(500)); j++ {
+			v := int64(fastrand.Uint32n(1000))
+			b.Add(v)
+			inB[v] = true
+		}
+		expected := func(keep func(x, y bool) bool) []int64 {
+			var res []int64
+			for v := int64(0); v < 1000; v++ {
+				if (inA[v] || inB[v]) && keep(inA[v], inB[v]) {
+					res = append(res, v)
+				}
+			}
+			return res
+		}
+		check := func(name string, got *Int64Set, keep func(x, y bool) bool) {
+			if !slices.Equal(slices.Collect(got.All()), expected(keep)) || got.Len() != len(expected(keep)) {
+				t.Fatal("invalid", name)
+			}
+		}
+		union := func(x, y bool) bool { return x || y }
+		intersect := func(x, y bool) bool { return x && y }
+		difference := func(x, y bool) bool { return x && !y }
+		symmetric := func(x, y bool) bool { return x != y }
+		check("union", Union(a, b), union)
+		check("intersect", Intersect(a, b), intersect)
+		check("difference", Difference(a, b), difference)
+		check("symmetric difference", SymmetricDifference(a, b), symmetric)
+
+		c := a.Clone()
+		c.UnionWith(b)
+		check("union with", c, union)
+		c = a.Clone()
+		c.IntersectWith(b)
+		check("intersect with", c, intersect)
+		checkRank(t, c)
+		c = a.Clone()
+		c.DifferenceWith(b)
+		check("difference with", c, difference)
+		c = a.Clone()
+		c.SymmetricDifferenceWith(b)
+		check("symmetric difference with", c, symmetric)
+	}
+
+	// The result has the order of the first skip set.
+	a, b := NewIntDesc(), NewIntDesc()
+	a.AddBatch([]int{1, 2, 3})
+	b.AddBatch([]int{3, 4})
+	if got := slices.Collect(Union(a, b).All()); !slices.Equal(got, []int{4, 3, 2, 1}) {
+		t.Fatal("invalid desc union", got)
+	}
+	if n := a.IntersectWith(a); n != 0 || a.Len() != 3 {
+		t.Fatal("invalid self intersect", n)
+	}
+
+	// The skip sets with different orders are rejected instead of returning a wrong result.
+	asc := NewInt()
+	asc.AddBatch([]int{1, 2, 3})
+	for name, f := range map[string]func(){
+		"union":          func() { Union(asc, a) },
+		"intersect":      func() { Intersect(asc, a) },
+		"intersect all":  func() { IntersectAll(asc, a) },
+		"symmetric":      func() { asc.SymmetricDifferenceWith(a) },
+		"intersect with": func() { asc.IntersectWith(a) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != orderMismatch {
+					t.Fatal("invalid panic of "+name, r)
+				}
+			}()
+			f()
+		}()
+	}
+	// The skip set is not modified by the rejected calls.
+	if asc.Len() != 3 {
+		t.Fatal("invalid rejected call")
+	}
+}
+
+func TestStringSetAlgebra(t *testing.T) {
+	a, b := NewString(), NewString()
+	a.AddBatch([]string{"a", "b", "c"})
+	b.AddBatch([]string{"c", "d"})
+	values := func(s *StringSet) []string {
+		var res []string
+		s.Range(func(value string) bool {
+			res = append(res, value)
+			return true
+		})
+		sort.Strings(res)
+		return res
+	}
+	if got := values(a.Union(b)); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+		t.Fatal("invalid union", got)
+	}
+	if got := values(a.Intersect(b)); !slices.Equal(got, []string{"c"}) {
+		t.Fatal("invalid intersect", got)
+	}
+	if got := values(a.Difference(b)); !slices.Equal(got, []string{"a", "b"}) {
+		t.Fatal("invalid difference", got)
+	}
+	if got := values(a.SymmetricDifference(b)); !slices.Equal(got, []string{"a", "b", "d"}) {
+		t.Fatal("invalid symmetric difference", got)
+	}
+	if removed, added := a.SymmetricDifferenceWith(b); removed != 1 || added != 1 {
+		t.Fatal("invalid symmetric difference with", removed, added)
+	}
+	if got := values(a); !slices.Equal(got, []string{"a", "b", "d"}) {
+		t.Fatal("invalid symmetric difference with", got)
+	}
+	if n := a.IntersectWith(b); n != 2 || !a.Contains("d") {
+		t.Fatal("invalid intersect with", n)
+	}
+	if n := a.UnionWith(b); n != 1 || a.Len() != 2 {
+		t.Fatal("invalid union with", n)
+	}
+	if n := a.DifferenceWith(b); n != 2 || a.Len() != 0 {
+		t.Fatal("invalid difference with", n)
+	}
+}
+
+func TestIntersectAll(t *testing.T) {
+	if IntersectAll[int]() != nil {
+		t.Fatal("invalid intersect all")
+	}
+	for i := 0; i < 20; i++ {
+		n := int(fastrand.Uint32n(6)) + 1
+		sets := make([]*Uint32Set, n)
+		counts := make(map[uint32]int)
+		for j := range sets {
+			sets[j] = NewUint32()
+			// The sets have different densities.
+			size := fastrand.Uint32n(2000)
+			mod := fastrand.Uint32n(5) + 1
+			for k := uint32(0); k < size; k++ {
+				v := fastrand.Uint32n(3000)
+				if v%mod == 0 && sets[j].Add(v) {
+					counts[v]++
+				}
+			}
+		}
+		var expected []uint32
+		for v, c := range counts {
+			if c == n {
+				expected = append(expected, v)
+			}
+		}
+		slices.Sort(expected)
+		if got := slices.Collect(IntersectAll(sets...).All()); !slices.Equal(got, expected) {
+			t.Fatal("invalid intersect all", n, got, expected)
+		}
+		var got []uint32
+		RangeIntersection(sets, func(value uint32) bool {
+			got = append(got, value)
+			return len(got) < 3
+		})
+		if len(got) != min(3, len(expected)) || !slices.Equal(got, expected[:len(got)]) {
+			t.Fatal("invalid range intersection", got)
+		}
+	}
+
+	a, b := NewUint32(), NewUint32()
+	a.AddBatch([]uint32{1, 2, 3})
+	b.AddBatch([]uint32{2, 3, 4})
+	if got := slices.Collect(IntersectAll(a, b, a).All()); !slices.Equal(got, []uint32{2, 3}) {
+		t.Fatal("invalid intersect all", got)
+	}
+}
@@ -0,0 +1,174 @@
+package skipset
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// AddBatch adds the values into the skip set, it returns the number of values added by this call.
+//
+// The values are sorted first, then the search of each value starts from the search result
+// of the previous value (a finger) instead of the header, which is much faster than calling
+// Add for each value if the values are close to each other. The values slice is not modified.
+func (s *Set[T]) AddBatch(values []T) int {
+	var (
+		preds, succs [maxLevel]*node[T]
+		count        int
+	)
+	values = s.sorted(values)
+	var last *list[T]
+	for i, value := range values {
+		if i > 0 && !s.less(values[i-1], value) {
+			continue // the finger must be less than the value, skip the duplicates
+		}
+		// The finger is dropped if the skip set is cleared.
+		l := s.load()
+		if s.add(l, value, &preds, &succs, i > 0 && l == last) {
+			count++
+		}
+		last = l
+	}
+	return count
+}
+
+// RemoveBatch removes the values from the skip set, it returns the number of values removed by this call.
+// See AddBatch for how the searches are shared.
+func (s *Set[T]) RemoveBatch(values []T) int {
+	var (
+		preds, succs [maxLevel]*node[T]
+		count        int
+	)
+	values = s.sorted(values)
+	var last *list[T]
+	for i, value := range values {
+		if i > 0 && !s.less(values[i-1], value) {
+			continue // the finger must be less than the value, skip the duplicates
+		}
+		l := s.load()
+		if s.remove(l, value, &preds, &succs, i > 0 && l == last) {
+			count++
+		}
+		last = l
+	}
+	return count
+}
+
+// ContainsMany checks if each value is in the skip set and stores the result in out,
+// out[i] is true if values[i] is in the skip set. It panics if out is shorter than values.
+//
+// The values should be sorted in the order of the skip set, then the search of each value starts
+// from the search result of the previous value, which costs much less than calling Contains for
+// each value. A value that is not greater than the previous one is searched from the header.
+// Like Contains, it is wait-free.
+func (s *Set[T]) ContainsMany(values []T, out []bool) {
+	out = out[:len(values)]
+	var (
+		preds, succs [maxLevel]*node[T]
+		last         *list[T]
+	)
+	for i, value := range values {
+		var lFound int
+		l := s.load()
+		if i > 0 && l == last && s.less(values[i-1], value) {
+			lFound = s.findNodeFinger(l, value, &preds, &succs)
+		} else {
+			lFound = s.findNodeRemove(l, value, &preds, &succs)
+		}
+		out[i] = lFound != -1 && s.present(succs[lFound])
+		last = l
+	}
+}
+
+// FilterPresent returns the values that are in the skip set, in the same order as they are
+// in values. See ContainsMany.
+func (s *Set[T]) FilterPresent(values []T) []T {
+	out := make([]bool, len(values))
+	s.ContainsMany(values, out)
+	var res []T
+	for i, ok := range out {
+		if ok {
+			res = append(res, values[i])
+		}
+	}
+	return res
+}
+
+// sorted returns the values sorted in the order of the skip set, the values are copied if they are not sorted.
+func (s *Set[T]) sorted(values []T) []T {
+	compare := func(a, b T) int {
+		if s.less(a, b) {
+			return -1
+		}
+		if s.less(b, a) {
+			return 1
+		}
+		return 0
+	}
+	if slices.IsSortedFunc(values, compare) {
+		return values
+	}
+	values = slices.Clone(values)
+	slices.SortFunc(values, compare)
+	return values
+}
+
+// findNodeFinger searches the value in the same way as findNodeRemove, but it starts from the preds
+// of a smaller value (the finger) instead of the header. Only the levels where the finger is behind
+// the value are walked, so the cost depends on the distance between the two values instead of the
+// length of the skip list. It falls back to findNodeRemove if the finger is no longer valid.
+func (s *Set[T]) findNodeFinger(l *list[T], value T, preds *[maxLevel]*node[T], succs *[maxLevel]*node[T]) int {
+	top := int(atomic.LoadInt64(&s.highestLevel)) - 1
+	// Find the lowest level at which the next node of the finger is not less than the value.
+	start := top
+	for i := 0; i < top; i++ {
+		pred := preds[i]
+		if pred == nil || pred.flags.Get(marked) {
+			return s.findNodeRemove(l, value, preds, succs)
+		}
+		succ := pred.atomicLoadNext(i)
+		if succ == nil || !s.less(succ.value, value) {
+			start = i
+			break
+		}
+	}
+	// The finger above the start level is still the search result of the value.
+	lFound := -1
+	for i := top; i >= start; i-- {
+		pred := preds[i]
+		if pred == nil || pred.flags.Get(marked) {
+			return s.findNodeRemove(l, value, preds, succs)
+		}
+		if i == start {
+			break
+		}
+		succ := pred.atomicLoadNext(i)
+		if succ != nil && s.less(succ.value, value) {
+			return s.findNodeRemove(l, value, preds, succs)
+		}
+		succs[i] = succ
+		if lFound == -1 && succ != nil && !s.less(value, succ.value) {
+			lFound = i
+		}
+	}
+	// Search downward from the start level.
+	x := preds[start]
+	for i := start; i >= 0; i-- {
+		// The finger in this level may be closer to the value.
+		if f := preds[i]; f != x && f != l.header && !f.flags.Get(marked) && (x == l.header || s.less(x.value, f.value)) {
+			x = f
+		}
+		succ := x.atomicLoadNext(i)
+		for succ != nil && s.less(succ.value, value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+		preds[i] = x
+		succs[i] = succ
+
+		// Check if the value already in the skip list.
+		if lFound == -1 && succ != nil && !s.less(value, succ.value) {
+			lFound = i
+		}
+	}
+	return lFound
+}
@@ -0,0 +1,185 @@
+package skipset
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestBatch(t *testing.T) {
+	s := NewInt64()
+	if s.AddBatch(nil) != 0 || s.RemoveBatch(nil) != 0 {
+		t.Fatal("invalid empty batch")
+	}
+	values := []int64{5, 3, 9, 3, 1}
+	if n := s.AddBatch(values); n != 4 || s.Len() != 4 {
+		t.Fatal("invalid add batch", n)
+	}
+	if !slices.Equal(values, []int64{5, 3, 9, 3, 1}) {
+		t.Fatal("the values are modified")
+	}
+	if n := s.AddBatch([]int64{1, 2, 3}); n != 1 {
+		t.Fatal("invalid add batch", n)
+	}
+	if got := slices.Collect(s.All()); !slices.Equal(got, []int64{1, 2, 3, 5, 9}) {
+		t.Fatal("invalid values", got)
+	}
+	if n := s.RemoveBatch([]int64{9, 4, 1, 1}); n != 2 {
+		t.Fatal("invalid remove batch", n)
+	}
+	if got := slices.Collect(s.All()); !slices.Equal(got, []int64{2, 3, 5}) {
+		t.Fatal("invalid values", got)
+	}
+
+	// Large batches with nearby values, the index is maintained.
+	s = NewIndexed[int64]()
+	var all []int64
+	for i := int64(0); i < 100000; i += int64(fastrand.Uint32n(5)) + 1 {
+		all = append(all, i)
+	}
+	if n := s.AddBatch(all); n != len(all) {
+		t.Fatal("invalid add batch", n)
+	}
+	if got := slices.Collect(s.All()); !slices.Equal(got, all) {
+		t.Fatal("invalid values")
+	}
+	checkRank(t, s)
+	if n := s.RemoveBatch(all[:len(all)/2]); n != len(all)/2 {
+		t.Fatal("invalid remove batch", n)
+	}
+	if got := slices.Collect(s.All()); !slices.Equal(got, all[len(all)/2:]) {
+		t.Fatal("invalid values")
+	}
+
+	// Concurrent batches, every value is added and removed by exactly one batch.
+	s = NewInt64()
+	var (
+		wg            sync.WaitGroup
+		added, remove int64
+		mu            sync.Mutex
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				batch := make([]int64, 100)
+				for k := range batch {
+					batch[k] = int64(fastrand.Uint32n(2000))
+				}
+				a := s.AddBatch(batch)
+				r := s.RemoveBatch(batch[:50])
+				mu.Lock()
+				added += int64(a)
+				remove += int64(r)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if added-remove != int64(s.Len()) {
+		t.Fatal("invalid length", added, remove, s.Len())
+	}
+	pre := int64(-1)
+	s.Range(func(value int64) bool {
+		if value <= pre {
+			t.Fatal("invalid order")
+		}
+		pre = value
+		return true
+	})
+}
+
+func BenchmarkAddBatch(b *testing.B) {
+	const batch = 1000
+	values := make([]int64, batch)
+	b.Run("Add", func(b *testing.B) {
+		s := NewInt64()
+		for i := 0; i < b.N; i += batch {
+			base := int64(fastrand.Uint32())
+			for j := range values {
+				s.Add(base + int64(j))
+			}
+		}
+	})
+	b.Run("AddBatch", func(b *testing.B) {
+		s := NewInt64()
+		for i := 0; i < b.N; i += batch {
+			base := int64(fastrand.Uint32())
+			for j := range values {
+				values[j] = base + int64(j)
+			}
+			s.AddBatch(values)
+		}
+	})
+}
+
+func TestStringSetBatch(t *testing.T) {
+	s := NewString()
+	if n := s.AddBatch([]string{"a", "b", "c", "a"}); n != 3 || s.Len() != 3 {
+		t.Fatal("invalid add batch", n)
+	}
+	if n := s.RemoveBatch([]string{"a", "d"}); n != 1 || s.Contains("a") || !s.Contains("b") {
+		t.Fatal("invalid remove batch", n)
+	}
+}
+
+func TestContainsMany(t *testing.T) {
+	s := NewInt64()
+	for i := int64(0); i < 10000; i += 3 {
+		s.Add(i)
+	}
+	probes := make([]int64, 0, 5000)
+	for i := int64(-10); i < 10010; i += int64(fastrand.Uint32n(8)) + 1 {
+		probes = append(probes, i)
+	}
+	// Unsorted and duplicated values are allowed.
+	probes = append(probes, 3, 3, 4, 0)
+	out := make([]bool, len(probes))
+	s.ContainsMany(probes, out)
+	var expected []int64
+	for i, v := range probes {
+		if out[i] != s.Contains(v) {
+			t.Fatal("invalid contains many", v)
+		}
+		if out[i] {
+			expected = append(expected, v)
+		}
+	}
+	if got := s.FilterPresent(probes); !slices.Equal(got, expected) {
+		t.Fatal("invalid filter present")
+	}
+	if len(s.FilterPresent(nil)) != 0 {
+		t.Fatal("invalid filter present")
+	}
+
+	// The values that are never removed must be found under concurrent modifications.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < 5000; j++ {
+				v := int64(fastrand.Uint32n(3333))*3 + 1
+				s.Add(v)
+				s.Remove(v)
+			}
+			wg.Done()
+		}()
+	}
+	fixed := make([]int64, 0, 3334)
+	for i := int64(0); i < 10000; i += 3 {
+		fixed = append(fixed, i)
+	}
+	out = make([]bool, len(fixed))
+	for i := 0; i < 20; i++ {
+		s.ContainsMany(fixed, out)
+		for j, ok := range out {
+			if !ok {
+				t.Fatal("invalid contains many", fixed[j])
+			}
+		}
+	}
+	wg.Wait()
+}
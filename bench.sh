@@ -1 +1 @@
-go run types_gen.go && go test -run=NOTEST -bench=. -cpu=1,2,4,8,16 -benchtime=100000x -count=20 -timeout=60m
\ No newline at end of file
+go test -run=NOTEST -bench=. -cpu=1,2,4,8,16 -benchtime=100000x -count=20 -timeout=60m
@@ -0,0 +1,64 @@
+package skipset
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrNotSorted is returned by the sorted builders if the values are not strictly sorted
+// in the order of the skip set.
+var ErrNotSorted = errors.New("skipset: values are not strictly sorted")
+
+// NewFromSorted return a skip set in ascending order that contains the values,
+// the values must be strictly ascending, otherwise an error wrapping ErrNotSorted is returned.
+//
+// It builds the skip set in O(n) without searching or locking, which is much faster than
+// adding the values one by one.
+//
+// The sorted builders are provided for all the skip set types except StringSet, whose values are
+// ordered by their hash instead of an order the callers can sort by, use NewStringLexFromSorted.
+func NewFromSorted[T cmp.Ordered](values []T) (*Set[T], error) {
+	return buildSorted(New[T](), values)
+}
+
+// NewDescFromSorted return a skip set in descending order that contains the values,
+// the values must be strictly descending. See NewFromSorted.
+func NewDescFromSorted[T cmp.Ordered](values []T) (*Set[T], error) {
+	return buildSorted(NewDesc[T](), values)
+}
+
+// NewFuncFromSorted return a skip set ordered by the less function that contains the values,
+// the values must be strictly sorted by the less function. See NewFromSorted.
+func NewFuncFromSorted[T any](less func(a, b T) bool, values []T) (*Set[T], error) {
+	return buildSorted(NewFunc(less), values)
+}
+
+// buildSorted links the sorted values into the empty skip set s, which is invisible to other goroutines.
+func buildSorted[T any](s *Set[T], values []T) (*Set[T], error) {
+	var (
+		l            = s.load()
+		last         [maxLevel]*node[T] // the last node in each level
+		highestLevel = defaultHighestLevel
+	)
+	for i := range last {
+		last[i] = l.header
+	}
+	for i, value := range values {
+		if i > 0 && !s.less(values[i-1], value) {
+			return nil, fmt.Errorf("%w: index %d", ErrNotSorted, i)
+		}
+		level := randomLevel()
+		highestLevel = max(highestLevel, level)
+		nn := newNode(value, level)
+		nn.flags.SetTrue(fullyLinked)
+		for layer := 0; layer < level; layer++ {
+			last[layer].storeNext(layer, nn)
+			last[layer] = nn
+		}
+	}
+	s.highestLevel = int64(highestLevel)
+	atomic.StoreInt64(&l.length, int64(len(values)))
+	return s, nil
+}
@@ -0,0 +1,54 @@
+package skipset
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNewFromSorted(t *testing.T) {
+	values := make([]int64, 1000)
+	for i := range values {
+		values[i] = int64(i * 2)
+	}
+	s, err := NewInt64FromSorted(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != len(values) || !slices.Equal(slices.Collect(s.All()), values) {
+		t.Fatal("invalid values")
+	}
+	if !s.Contains(100) || s.Contains(101) {
+		t.Fatal("invalid contains")
+	}
+	// The skip set built works as usual.
+	if !s.Add(101) || !s.Remove(100) || s.Len() != len(values) {
+		t.Fatal("invalid modification")
+	}
+	checkRank(t, s)
+
+	if _, err := NewInt64FromSorted([]int64{1, 2, 2}); !errors.Is(err, ErrNotSorted) {
+		t.Fatal("invalid error", err)
+	}
+	if _, err := NewFloat64FromSorted([]float64{3, 1}); !errors.Is(err, ErrNotSorted) {
+		t.Fatal("invalid error", err)
+	}
+
+	d, err := NewIntDescFromSorted([]int{3, 2, 1})
+	if err != nil || !slices.Equal(slices.Collect(d.All()), []int{3, 2, 1}) {
+		t.Fatal("invalid desc", err)
+	}
+	if _, err := NewIntDescFromSorted([]int{1, 2}); !errors.Is(err, ErrNotSorted) {
+		t.Fatal("invalid error", err)
+	}
+
+	e, err := NewStringLexFromSorted(nil)
+	if err != nil || e.Len() != 0 || !e.Add("") {
+		t.Fatal("invalid empty", err)
+	}
+
+	f, err := NewFuncFromSorted(func(a, b int) bool { return a%10 < b%10 }, []int{10, 21, 32})
+	if err != nil || !f.Contains(2) || f.Contains(3) {
+		t.Fatal("invalid func", err)
+	}
+}
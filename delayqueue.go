@@ -0,0 +1,108 @@
+package skipset
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// DelayQueue is a concurrent-safe queue of values with deadlines, a value can only be
+// taken after its deadline has passed. The values are kept in a skip set ordered by
+// their deadlines in nanoseconds, the values with the same deadline are taken in the
+// order they were put.
+type DelayQueue[T any] struct {
+	set *Set[delayItem[T]]
+	seq uint64 // tiebreaker for the values with the same deadline
+}
+
+type delayItem[T any] struct {
+	deadline int64 // unix time in nanoseconds
+	seq      uint64
+	value    T
+}
+
+func lessDelayItem[T any](a, b delayItem[T]) bool {
+	if a.deadline != b.deadline {
+		return a.deadline < b.deadline
+	}
+	return a.seq < b.seq
+}
+
+// NewDelayQueue return an empty delay queue.
+func NewDelayQueue[T any]() *DelayQueue[T] {
+	return &DelayQueue[T]{set: newSet(lessDelayItem[T], nil)}
+}
+
+// Put adds the value into the queue, it can be taken after the deadline.
+func (q *DelayQueue[T]) Put(value T, deadline time.Time) {
+	q.set.Add(delayItem[T]{
+		deadline: deadline.UnixNano(),
+		seq:      atomic.AddUint64(&q.seq, 1),
+		value:    value,
+	})
+}
+
+// TryTake removes the value with the earliest deadline from the queue and returns it,
+// ok is false if the queue is empty or the earliest deadline has not passed yet.
+func (q *DelayQueue[T]) TryTake() (value T, ok bool) {
+	for {
+		item, ok := q.set.Min()
+		if !ok || item.deadline > time.Now().UnixNano() {
+			return value, false
+		}
+		// Remove the item itself, PopMin may remove another item whose deadline has not passed
+		// if the item is taken by another process.
+		if q.set.Remove(item) {
+			return item.value, true
+		}
+	}
+}
+
+// Take removes the value with the earliest deadline from the queue and returns it,
+// it waits until the earliest deadline has passed or the ctx is done. The returned
+// error is ctx.Err() if the ctx is done before a value is taken.
+func (q *DelayQueue[T]) Take(ctx context.Context) (value T, err error) {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for {
+		// Register before checking the earliest item, so that an item put with
+		// an earlier deadline after the check wakes us up.
+		ch := q.set.added.register()
+		var expired <-chan time.Time
+		if item, ok := q.set.Min(); ok {
+			delay := time.Duration(item.deadline - time.Now().UnixNano())
+			if delay <= 0 {
+				q.set.added.unregister()
+				if q.set.Remove(item) {
+					return item.value, nil
+				}
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(delay)
+			} else {
+				timer.Reset(delay)
+			}
+			expired = timer.C
+		}
+		select {
+		case <-ch:
+		case <-expired:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		q.set.added.unregister()
+		if err != nil {
+			return value, err
+		}
+	}
+}
+
+// Len return the number of values in the queue, including the values whose deadlines have not passed.
+func (q *DelayQueue[T]) Len() int {
+	return q.set.Len()
+}
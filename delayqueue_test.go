@@ -0,0 +1,93 @@
+package skipset
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDelayQueue(t *testing.T) {
+	q := NewDelayQueue[string]()
+	if _, ok := q.TryTake(); ok {
+		t.Fatal("invalid take")
+	}
+	now := time.Now()
+	q.Put("b", now.Add(-time.Second))
+	q.Put("a", now.Add(-2*time.Second))
+	q.Put("c", now.Add(-time.Second)) // same deadline as b, taken after b
+	q.Put("later", now.Add(time.Hour))
+	if q.Len() != 4 {
+		t.Fatal("invalid length")
+	}
+	for _, expected := range []string{"a", "b", "c"} {
+		if v, ok := q.TryTake(); !ok || v != expected {
+			t.Fatal("invalid take", v, expected)
+		}
+	}
+	if _, ok := q.TryTake(); ok {
+		t.Fatal("invalid take")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Take(ctx); err != context.DeadlineExceeded {
+		t.Fatal("invalid error", err)
+	}
+
+	// An earlier value put while Take is waiting.
+	start := time.Now()
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		q.Put("soon", time.Now().Add(20*time.Millisecond))
+	}()
+	v, err := q.Take(context.Background())
+	if err != nil || v != "soon" {
+		t.Fatal("invalid take", v, err)
+	}
+	if time.Since(start) < 25*time.Millisecond {
+		t.Fatal("taken before the deadline")
+	}
+}
+
+func TestDelayQueueConcurrent(t *testing.T) {
+	const (
+		workers = 8
+		num     = 2000
+	)
+	var (
+		q      = NewDelayQueue[int]()
+		wg     sync.WaitGroup
+		taken  [num]int32
+		total  int64
+		ctx, c = context.WithCancel(context.Background())
+	)
+	defer c()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				v, err := q.Take(ctx)
+				if err != nil {
+					return
+				}
+				atomic.AddInt32(&taken[v], 1)
+				if atomic.AddInt64(&total, 1) == num {
+					c()
+				}
+			}
+		}()
+	}
+	now := time.Now()
+	for i := 0; i < num; i++ {
+		q.Put(i, now.Add(time.Duration(i%20)*time.Millisecond))
+	}
+	wg.Wait()
+	for i, n := range taken {
+		if n != 1 {
+			t.Fatalf("%d is taken %d times", i, n)
+		}
+	}
+}
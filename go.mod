@@ -1,8 +1,10 @@
 module github.com/zhangyunhao116/skipset
 
-go 1.13
+go 1.23
 
 require (
 	github.com/zhangyunhao116/fastrand v0.1.0
 	github.com/zhangyunhao116/wyhash v0.3.2
 )
+
+require github.com/zhangyunhao116/sbconv v0.2.1 // indirect
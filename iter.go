@@ -0,0 +1,38 @@
+package skipset
+
+import (
+	"cmp"
+	"iter"
+)
+
+// All returns an iterator over the values in the skip set, in the same way as Range.
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.Range(yield)
+	}
+}
+
+// Backward returns an iterator over the values in the skip set in reverse order,
+// in the same way as RangeReverse.
+func (s *Set[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.RangeReverse(yield)
+	}
+}
+
+// Between returns an iterator over the values in the skip set between lo and hi,
+// in the same way as RangeBetween.
+func (s *Set[T]) Between(lo, hi T, bounds Bounds) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.RangeBetween(lo, hi, bounds, yield)
+	}
+}
+
+// Collect collects values from seq into a new skip set in ascending order.
+func Collect[T cmp.Ordered](seq iter.Seq[T]) *Set[T] {
+	s := New[T]()
+	for v := range seq {
+		s.Add(v)
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package skipset
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestIterSeq(t *testing.T) {
+	s := Collect(slices.Values([]int{5, 1, 4, 1, 3, 2}))
+	if got := slices.Collect(s.All()); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Fatal("invalid all", got)
+	}
+	if got := slices.Collect(s.Backward()); !slices.Equal(got, []int{5, 4, 3, 2, 1}) {
+		t.Fatal("invalid backward", got)
+	}
+	if got := slices.Collect(s.Between(2, 4, ClosedOpen)); !slices.Equal(got, []int{2, 3}) {
+		t.Fatal("invalid between", got)
+	}
+	if got := slices.Collect(s.Between(2, 4, Closed)); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Fatal("invalid between", got)
+	}
+	for v := range s.All() {
+		if v == 3 {
+			break
+		}
+	}
+
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	x := NewString()
+	for k := range maps.Keys(m) {
+		x.Add(k)
+	}
+	got := slices.Sorted(x.All())
+	if !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Fatal("invalid all", got)
+	}
+	if y := Collect(maps.Keys(m)); y.Len() != 3 || !y.Contains("b") {
+		t.Fatal("invalid collect")
+	}
+}
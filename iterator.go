@@ -0,0 +1,72 @@
+package skipset
+
+// Iterator is a stateful iterator over the values of a skip set, see Set.Iter.
+//
+// Like Range, the iterator never blocks other goroutines and it is wait-free as long as
+// the current node is not removed. The values added or removed during the iteration may or
+// may not be observed. An Iterator must not be used by multiple goroutines at the same time,
+// but it can be handed over to another goroutine to resume the iteration.
+type Iterator[T any] struct {
+	s    *Set[T]
+	x    *node[T] // the current node, nil if the iterator is not positioned at a value
+	done bool     // the iterator has moved past the last value
+}
+
+// Iter returns an iterator positioned before the first value of the skip set.
+//
+// A typical usage:
+//
+//	it := s.Iter()
+//	for it.Next() {
+//		fmt.Println(it.Value())
+//	}
+func (s *Set[T]) Iter() *Iterator[T] {
+	return &Iterator[T]{s: s}
+}
+
+// SeekFirst moves the iterator to the smallest value of the skip set,
+// it returns false if the skip set is empty.
+func (it *Iterator[T]) SeekFirst() bool {
+	return it.moveTo(it.s.findFirstValid(it.s.load().header.atomicLoadNext(0)))
+}
+
+// Seek moves the iterator to the smallest value greater than or equal to the given value,
+// it returns false if there is no such value.
+func (it *Iterator[T]) Seek(value T) bool {
+	x := it.s.findLast(it.s.load(), func(v T) bool { return it.s.less(v, value) })
+	return it.moveTo(it.s.findFirstValid(x.atomicLoadNext(0)))
+}
+
+// Next moves the iterator to the next value, it returns false if there is no more value.
+// If the iterator is not positioned yet, Next moves it to the smallest value.
+func (it *Iterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+	if it.x == nil {
+		return it.SeekFirst()
+	}
+	x := it.x
+	if x.flags.Get(marked) {
+		// The current node has been removed, the nodes after it may be changed
+		// without updating its next pointers, so find the next value from the header.
+		x = it.s.findLast(it.s.load(), func(v T) bool { return !it.s.less(it.x.value, v) })
+	}
+	return it.moveTo(it.s.findFirstValid(x.atomicLoadNext(0)))
+}
+
+// Value returns the value at the current position, or the zero value if the iterator
+// is not positioned at a value.
+func (it *Iterator[T]) Value() T {
+	if it.x == nil {
+		var zero T
+		return zero
+	}
+	return it.x.value
+}
+
+func (it *Iterator[T]) moveTo(x *node[T]) bool {
+	it.x = x
+	it.done = x == nil
+	return !it.done
+}
@@ -0,0 +1,92 @@
+package skipset
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	s := NewInt64()
+	it := s.Iter()
+	if it.Next() || it.SeekFirst() || it.Seek(0) || it.Value() != 0 {
+		t.Fatal("invalid empty iterator")
+	}
+
+	for i := int64(0); i < 100; i += 2 {
+		s.Add(i)
+	}
+	it = s.Iter()
+	var i int64
+	for it.Next() {
+		if it.Value() != i {
+			t.Fatal("invalid value", it.Value())
+		}
+		i += 2
+	}
+	if i != 100 || it.Next() {
+		t.Fatal("invalid iteration")
+	}
+
+	if !it.Seek(51) || it.Value() != 52 {
+		t.Fatal("invalid seek", it.Value())
+	}
+	if !it.Next() || it.Value() != 54 {
+		t.Fatal("invalid next", it.Value())
+	}
+	if it.Seek(99) {
+		t.Fatal("invalid seek")
+	}
+	if !it.SeekFirst() || it.Value() != 0 {
+		t.Fatal("invalid seek first")
+	}
+
+	// Remove the current node and the nodes after it, then add new values.
+	if !it.Seek(10) {
+		t.Fatal("invalid seek")
+	}
+	s.Remove(10)
+	s.Remove(12)
+	s.Add(11)
+	if !it.Next() || it.Value() != 11 {
+		t.Fatal("invalid next after remove", it.Value())
+	}
+
+	// Concurrent remove the odd values while iterating.
+	s = NewInt64()
+	for i := int64(0); i < 1000; i++ {
+		s.Add(i)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		for i := int64(1); i < 1000; i += 2 {
+			s.Remove(i)
+		}
+		wg.Done()
+	}()
+	pre := int64(-1)
+	for it := s.Iter(); it.Next(); {
+		if it.Value() <= pre {
+			t.Fatal("invalid iteration", it.Value())
+		}
+		pre = it.Value()
+	}
+	wg.Wait()
+
+	x := NewString()
+	for i := 0; i < 10; i++ {
+		x.Add(strconv.Itoa(i))
+	}
+	var all []string
+	for it := x.Iter(); it.Next(); {
+		all = append(all, it.Value())
+	}
+	if len(all) != 10 {
+		t.Fatal("invalid iteration")
+	}
+	xit := x.Iter()
+	if !xit.Seek(all[5]) || xit.Value() != all[5] || !xit.Next() || xit.Value() != all[6] {
+		t.Fatal("invalid seek")
+	}
+}
@@ -0,0 +1,93 @@
+package skipset
+
+import "cmp"
+
+// isFloat reports whether T is a floating-point type.
+func isFloat[T Number]() bool {
+	var half T = 1
+	half /= 2
+	return half != 0
+}
+
+// distance returns |a - b| as a float64 for the floats or as a uint64 for the integers, the other
+// one is zero. The difference of two signed integers may overflow T (e.g. int8(90) - int8(-100)),
+// but the uint64 wraps around to the exact distance since it is at most the range of T.
+func distance[T Number](a, b T) (du uint64, df float64) {
+	if a < b {
+		a, b = b, a
+	}
+	if isFloat[T]() {
+		return 0, float64(a) - float64(b)
+	}
+	return uint64(a) - uint64(b), 0
+}
+
+// closer reports whether a is closer to value than b, the smaller one wins a tie.
+func closer[T Number](value, a, b T) bool {
+	au, af := distance(a, value)
+	bu, bf := distance(b, value)
+	c := cmp.Or(cmp.Compare(au, bu), cmp.Compare(af, bf))
+	return c < 0 || c == 0 && a < b
+}
+
+// Nearest returns the value in the skip set closest to the given value by absolute distance,
+// the smaller one is returned if there are two such values. ok is false if the skip set is empty.
+//
+// It costs two searches, see Floor and Ceiling.
+func Nearest[T Number](s *Set[T], value T) (nearest T, ok bool) {
+	floor, fok := s.Floor(value)
+	ceil, cok := s.Ceiling(value)
+	switch {
+	case fok && cok:
+		if closer(value, ceil, floor) {
+			return ceil, true
+		}
+		return floor, true
+	case fok:
+		return floor, true
+	default:
+		return ceil, cok
+	}
+}
+
+// KNearest returns at most k values in the skip set closest to the given value, sorted by
+// the absolute distance, the smaller one comes first if two values have the same distance.
+//
+// It searches the neighbors of the value once, then expands outward. The values after the
+// value in the order of the skip set are visited via the links in level 0, the values before
+// it need a search for each value because the nodes have no backward links.
+func KNearest[T Number](s *Set[T], value T, k int) []T {
+	if k <= 0 {
+		return nil
+	}
+	var (
+		res      = make([]T, 0, min(k, s.Len()))
+		it       = s.Iter()
+		nok      = it.Seek(value)
+		prev, ok = s.Lower(value)
+	)
+	for len(res) < k && (ok || nok) {
+		if nok && (!ok || closer(value, it.Value(), prev)) {
+			res = append(res, it.Value())
+			nok = it.Next()
+		} else {
+			res = append(res, prev)
+			prev, ok = s.Lower(prev)
+		}
+	}
+	return res
+}
+
+// ContainsWithin reports whether the skip set contains a value v that satisfies |v - value| <= eps,
+// which is useful for matching floating-point values with a tolerance.
+func ContainsWithin[T Number](s *Set[T], value, eps T) bool {
+	nearest, ok := Nearest(s, value)
+	if !ok || eps < 0 {
+		return false
+	}
+	du, df := distance(nearest, value)
+	if isFloat[T]() {
+		return df <= float64(eps)
+	}
+	return du <= uint64(eps)
+}
@@ -0,0 +1,92 @@
+package skipset
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestNearest(t *testing.T) {
+	s := NewFloat64()
+	if _, ok := Nearest(s, 1); ok {
+		t.Fatal("invalid nearest")
+	}
+	if len(KNearest(s, 1, 3)) != 0 {
+		t.Fatal("invalid k nearest")
+	}
+	if ContainsWithin(s, 1, 1) {
+		t.Fatal("invalid contains within")
+	}
+	for _, v := range []float64{1, 2, 4, 8, 16} {
+		s.Add(v)
+	}
+	for _, tc := range []struct {
+		value, expected float64
+	}{{-5, 1}, {1, 1}, {1.4, 1}, {1.5, 1}, {1.6, 2}, {3, 2}, {3.1, 4}, {12, 8}, {100, 16}} {
+		if v, ok := Nearest(s, tc.value); !ok || v != tc.expected {
+			t.Fatalf("invalid nearest of %v: expected %v, got %v", tc.value, tc.expected, v)
+		}
+	}
+	if res := KNearest(s, 3, 3); fmt.Sprint(res) != "[2 4 1]" {
+		t.Fatal("invalid k nearest", res)
+	}
+	if res := KNearest(s, 10, 10); fmt.Sprint(res) != "[8 4 16 2 1]" {
+		t.Fatal("invalid k nearest", res)
+	}
+	if res := KNearest(s, 10, 0); len(res) != 0 {
+		t.Fatal("invalid k nearest", res)
+	}
+	if !ContainsWithin(s, 4.0001, 0.001) || ContainsWithin(s, 4.01, 0.001) || !ContainsWithin(s, math.Inf(1), math.Inf(1)) {
+		t.Fatal("invalid contains within")
+	}
+
+	// Unsigned values and descending order.
+	u := NewUint32Desc()
+	for _, v := range []uint32{0, 10, 20} {
+		u.Add(v)
+	}
+	if v, _ := Nearest(u, 14); v != 10 {
+		t.Fatal("invalid nearest", v)
+	}
+	if v, _ := Nearest(u, 15); v != 10 {
+		t.Fatal("invalid nearest", v)
+	}
+	if res := KNearest(u, 16, 3); fmt.Sprint(res) != "[20 10 0]" {
+		t.Fatal("invalid k nearest", res)
+	}
+	if !ContainsWithin(u, 3, 3) || ContainsWithin(u, 5, 4) {
+		t.Fatal("invalid contains within")
+	}
+
+	// The distances of the signed integers never overflow.
+	i := New[int8]()
+	i.Add(-100)
+	if ContainsWithin(i, 100, 0) || ContainsWithin(i, 100, 127) || !ContainsWithin(i, 27, 127) {
+		t.Fatal("invalid contains within")
+	}
+	i.Add(90)
+	if v, _ := Nearest(i, 50); v != 90 {
+		t.Fatal("invalid nearest", v)
+	}
+	if v, _ := Nearest(i, -6); v != -100 {
+		t.Fatal("invalid nearest", v)
+	}
+	if res := KNearest(i, math.MinInt8, 2); fmt.Sprint(res) != "[-100 90]" {
+		t.Fatal("invalid k nearest", res)
+	}
+	if res := KNearest(i, math.MaxInt8, 2); fmt.Sprint(res) != "[90 -100]" {
+		t.Fatal("invalid k nearest", res)
+	}
+	m := NewInt64()
+	m.Add(math.MinInt64)
+	m.Add(math.MaxInt64)
+	if v, _ := Nearest(m, 0); v != math.MaxInt64 {
+		t.Fatal("invalid nearest", v)
+	}
+	if v, _ := Nearest(m, -1); v != math.MinInt64 {
+		t.Fatal("invalid nearest", v)
+	}
+	if ContainsWithin(m, 0, math.MaxInt64-1) || !ContainsWithin(m, -1, math.MaxInt64) {
+		t.Fatal("invalid contains within")
+	}
+}
@@ -0,0 +1,47 @@
+package skipset
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// notifier wakes up the goroutines waiting for a new value.
+// If nobody is waiting, notify only costs an atomic load.
+type notifier struct {
+	waiters int32
+	mu      sync.Mutex
+	ch      chan struct{} // closed and reset by notify, nil if nobody has waited since the last notify
+}
+
+// register returns a channel that will be closed by the next notify,
+// the caller must call unregister after it stops waiting.
+//
+// The caller should check its condition again after register, because the
+// notify may happen before register.
+func (n *notifier) register() <-chan struct{} {
+	atomic.AddInt32(&n.waiters, 1)
+	n.mu.Lock()
+	if n.ch == nil {
+		n.ch = make(chan struct{})
+	}
+	ch := n.ch
+	n.mu.Unlock()
+	return ch
+}
+
+func (n *notifier) unregister() {
+	atomic.AddInt32(&n.waiters, -1)
+}
+
+// notify wakes up all the goroutines waiting on the channels returned by register.
+func (n *notifier) notify() {
+	if atomic.LoadInt32(&n.waiters) == 0 {
+		return
+	}
+	n.mu.Lock()
+	if n.ch != nil {
+		close(n.ch)
+		n.ch = nil
+	}
+	n.mu.Unlock()
+}
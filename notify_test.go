@@ -0,0 +1,84 @@
+package skipset
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPopMinWait(t *testing.T) {
+	s := NewInt64()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.PopMinWait(ctx); err != context.DeadlineExceeded {
+		t.Fatal("invalid error", err)
+	}
+	if atomic.LoadInt32(&s.added.waiters) != 0 {
+		t.Fatal("waiter is not unregistered")
+	}
+
+	s.Add(1)
+	if v, err := s.PopMinWait(context.Background()); err != nil || v != 1 {
+		t.Fatal("invalid pop", v, err)
+	}
+
+	// Workers wait for the values added later.
+	const (
+		workers = 8
+		num     = 10000
+	)
+	var (
+		wg     sync.WaitGroup
+		popped [num]int32
+		total  int64
+	)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				v, err := s.PopMinWait(ctx)
+				if err != nil {
+					return
+				}
+				atomic.AddInt32(&popped[v], 1)
+				if atomic.AddInt64(&total, 1) == num {
+					cancel()
+				}
+			}
+		}()
+	}
+	for i := int64(0); i < num; i++ {
+		s.Add(i)
+	}
+	wg.Wait()
+	for i, n := range popped {
+		if n != 1 {
+			t.Fatalf("%d is popped %d times", i, n)
+		}
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	var n notifier
+	n.notify() // nobody is waiting
+	ch := n.register()
+	n.notify()
+	select {
+	case <-ch:
+	default:
+		t.Fatal("not notified")
+	}
+	n.unregister()
+	ch = n.register()
+	select {
+	case <-ch:
+		t.Fatal("invalid notify")
+	default:
+	}
+	n.unregister()
+}
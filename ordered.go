@@ -0,0 +1,102 @@
+package skipset
+
+import "cmp"
+
+// searcher is the hot searching functions of a skip set specialized for its order, see Set.ordered.
+// They are the same as the methods of Set with the same names, x is the header of the skip list
+// and top is the highest level to search.
+//
+// The preds and succs are returned instead of being written via pointers, which would make the
+// arrays of the callers escape to the heap.
+type searcher[T any] interface {
+	findNodeRemove(x *node[T], top int, value T) searchResult[T]
+	findNodeAdd(x *node[T], top int, value T) searchResult[T]
+	findNode(x *node[T], top int, value T) *node[T]
+}
+
+// searchResult is the result of findNodeRemove and findNodeAdd, the preds of the levels not
+// searched are nil.
+type searchResult[T any] struct {
+	preds, succs [maxLevel]*node[T]
+	lFound       int
+}
+
+// store stores the searched levels below top into preds and succs, and returns lFound.
+func (r *searchResult[T]) store(top int, preds, succs *[maxLevel]*node[T]) int {
+	for i := 0; i < top; i++ {
+		if r.preds[i] != nil {
+			preds[i], succs[i] = r.preds[i], r.succs[i]
+		}
+	}
+	return r.lFound
+}
+
+// orderedSearch is the searcher of the skip sets created by New and NewDesc, it compares the values
+// via the operators instead of calling the less function of the skip set for every comparison.
+type orderedSearch[T cmp.Ordered] struct {
+	desc bool
+}
+
+// less is the same as the less function of the skip set, it keeps the NaN order of cmp.Less.
+func (o orderedSearch[T]) less(a, b T) bool {
+	if o.desc {
+		return cmp.Less(b, a)
+	}
+	return cmp.Less(a, b)
+}
+
+func (o orderedSearch[T]) findNodeRemove(x *node[T], top int, value T) (r searchResult[T]) {
+	// lFound represents the index of the first layer at which it found a node.
+	r.lFound = -1
+	for i := top - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && o.less(succ.value, value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+		r.preds[i] = x
+		r.succs[i] = succ
+
+		// Check if the value already in the skip list.
+		if r.lFound == -1 && succ != nil && !o.less(value, succ.value) {
+			r.lFound = i
+		}
+	}
+	return r
+}
+
+func (o orderedSearch[T]) findNodeAdd(x *node[T], top int, value T) (r searchResult[T]) {
+	r.lFound = -1
+	for i := top - 1; i >= 0; i-- {
+		succ := x.atomicLoadNext(i)
+		for succ != nil && o.less(succ.value, value) {
+			x = succ
+			succ = x.atomicLoadNext(i)
+		}
+		r.preds[i] = x
+		r.succs[i] = succ
+
+		// Check if the value already in the skip list.
+		if succ != nil && !o.less(value, succ.value) {
+			r.lFound = i
+			return r
+		}
+	}
+	return r
+}
+
+func (o orderedSearch[T]) findNode(x *node[T], top int, value T) *node[T] {
+	for i := top - 1; i >= 0; i-- {
+		nex := x.atomicLoadNext(i)
+		for nex != nil && o.less(nex.value, value) {
+			x = nex
+			nex = x.atomicLoadNext(i)
+		}
+
+		// Check if the value already in the skip list.
+		if nex != nil && !o.less(value, nex.value) {
+			return nex
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package skipset
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestOrderedSearch(t *testing.T) {
+	desc := func(a, b float64) bool { return cmp.Less(b, a) }
+	for _, c := range []struct {
+		fast, slow *Set[float64]
+	}{
+		{New[float64](), NewFunc(cmp.Less[float64])},
+		{NewDesc[float64](), NewFunc(desc)},
+	} {
+		if c.fast.ordered == nil || c.slow.ordered != nil {
+			t.Fatal("invalid searcher")
+		}
+		// The NaN is ordered as cmp.Less.
+		c.fast.Add(math.NaN())
+		c.slow.Add(math.NaN())
+		for i := 0; i < 1000; i++ {
+			v := float64(fastrand.Intn(200))
+			switch fastrand.Intn(3) {
+			case 0:
+				if c.fast.Add(v) != c.slow.Add(v) {
+					t.Fatal("invalid add", v)
+				}
+			case 1:
+				if c.fast.Remove(v) != c.slow.Remove(v) {
+					t.Fatal("invalid remove", v)
+				}
+			default:
+				if c.fast.Contains(v) != c.slow.Contains(v) {
+					t.Fatal("invalid contains", v)
+				}
+			}
+		}
+		if !c.fast.Contains(math.NaN()) || c.fast.Len() != c.slow.Len() {
+			t.Fatal("invalid length")
+		}
+		fast, slow := slices.Collect(c.fast.All()), slices.Collect(c.slow.All())
+		if !slices.EqualFunc(fast, slow, func(a, b float64) bool { return cmp.Compare(a, b) == 0 }) {
+			t.Fatal("invalid values")
+		}
+		if !c.fast.Remove(math.NaN()) || c.fast.Contains(math.NaN()) {
+			t.Fatal("invalid remove")
+		}
+	}
+
+	// The skip sets derived from an ordered one are also ordered.
+	a := New[int]()
+	a.Add(1)
+	for _, s := range []*Set[int]{
+		NewIndexed[int](), NewIndexedDesc[int](), NewSum[int]().Set,
+		a.Clone(), a.Snapshot().Clone(), Union(a, a), IntersectAll(a, a),
+	} {
+		if s.ordered == nil {
+			t.Fatal("invalid searcher")
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package skipset
+
+import "sync/atomic"
+
+// Min returns the smallest value in the skip set, ok is false if the skip set is empty.
+func (s *Set[T]) Min() (value T, ok bool) {
+	return nodeValue(s.findFirstValid(s.load().header.atomicLoadNext(0)))
+}
+
+// Max returns the largest value in the skip set, ok is false if the skip set is empty.
+func (s *Set[T]) Max() (value T, ok bool) {
+	return nodeValue(s.findLastValid(s.load(), func(T) bool { return true }))
+}
+
+// Ceiling returns the smallest value in the skip set greater than or equal to the given value,
+// ok is false if there is no such value.
+func (s *Set[T]) Ceiling(value T) (T, bool) {
+	x := s.findLast(s.load(), func(v T) bool { return s.less(v, value) })
+	return nodeValue(s.findFirstValid(x.atomicLoadNext(0)))
+}
+
+// Higher returns the smallest value in the skip set strictly greater than the given value,
+// ok is false if there is no such value.
+func (s *Set[T]) Higher(value T) (T, bool) {
+	x := s.findLast(s.load(), func(v T) bool { return !s.less(value, v) })
+	return nodeValue(s.findFirstValid(x.atomicLoadNext(0)))
+}
+
+// Floor returns the largest value in the skip set less than or equal to the given value,
+// ok is false if there is no such value.
+func (s *Set[T]) Floor(value T) (T, bool) {
+	return nodeValue(s.findLastValid(s.load(), func(v T) bool { return !s.less(value, v) }))
+}
+
+// Lower returns the largest value in the skip set strictly less than the given value,
+// ok is false if there is no such value.
+func (s *Set[T]) Lower(value T) (T, bool) {
+	return nodeValue(s.findLastValid(s.load(), func(v T) bool { return s.less(v, value) }))
+}
+
+// findFirstValid returns the first fully linked and unmarked node starting from x, or nil if there is no such node.
+func (s *Set[T]) findFirstValid(x *node[T]) *node[T] {
+	for x != nil && !s.present(x) {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
+// nodeValue returns the value of n, ok is false if n is nil.
+func nodeValue[T any](n *node[T]) (value T, ok bool) {
+	if n == nil {
+		return value, false
+	}
+	return n.value, true
+}
+
+// findLastValid returns the last fully linked and unmarked node in l whose value satisfies before,
+// or nil if there is no such node.
+func (s *Set[T]) findLastValid(l *list[T], before func(value T) bool) *node[T] {
+	x := s.findLast(l, before)
+	for x != l.header {
+		if s.present(x) {
+			return x
+		}
+		// The node is being inserted or removed, try the nodes before it.
+		bound := x.value
+		x = s.findLast(l, func(v T) bool { return s.less(v, bound) })
+	}
+	return nil
+}
+
+// findLast returns the last node in l whose value satisfies before, or the header if there is no such node.
+// The before must hold for a prefix of the skip set, i.e. it holds for all the values less than
+// a value that satisfies it.
+func (s *Set[T]) findLast(l *list[T], before func(value T) bool) *node[T] {
+	x := l.header
+	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+		nex := x.atomicLoadNext(i)
+		for nex != nil && before(nex.value) {
+			x = nex
+			nex = x.atomicLoadNext(i)
+		}
+	}
+	return x
+}
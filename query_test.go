@@ -0,0 +1,160 @@
+package skipset
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestMinMax(t *testing.T) {
+	s := NewInt64()
+	if _, ok := s.Min(); ok {
+		t.Fatal("invalid min")
+	}
+	if _, ok := s.Max(); ok {
+		t.Fatal("invalid max")
+	}
+	for _, v := range []int64{5, -3, 12, 7} {
+		s.Add(v)
+	}
+	if v, ok := s.Min(); !ok || v != -3 {
+		t.Fatal("invalid min", v)
+	}
+	if v, ok := s.Max(); !ok || v != 12 {
+		t.Fatal("invalid max", v)
+	}
+	d := NewDesc[int64]()
+	for _, v := range []int64{5, -3, 12, 7} {
+		d.Add(v)
+	}
+	if v, _ := d.Min(); v != 12 {
+		t.Fatal("invalid min", v)
+	}
+	if v, _ := d.Max(); v != -3 {
+		t.Fatal("invalid max", v)
+	}
+
+	// The min and max must be in the set while others keep adding and removing.
+	s = NewInt64()
+	s.Add(0)
+	s.Add(1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < 1000; j++ {
+				v := int64(fastrand.Uint32n(998)) + 1
+				s.Add(v)
+				s.Remove(v)
+			}
+			wg.Done()
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		if v, _ := s.Min(); v != 0 {
+			t.Fatal("invalid min", v)
+		}
+		if v, _ := s.Max(); v != 1000 {
+			t.Fatal("invalid max", v)
+		}
+	}
+	wg.Wait()
+
+	x := NewString()
+	x.Add("a")
+	min, _ := x.Min()
+	max, _ := x.Max()
+	if min != "a" || max != "a" {
+		t.Fatal("invalid")
+	}
+}
+
+func TestNavigation(t *testing.T) {
+	const num = 1000
+	s := NewInt64()
+	var all []int64
+	for i := 0; i < num; i++ {
+		v := int64(fastrand.Uint32n(num * 4))
+		if s.Add(v) {
+			all = append(all, v)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+	check := func(name string, got int64, ok bool, idx int) {
+		if idx < 0 || idx >= len(all) {
+			if ok {
+				t.Fatalf("%s: expected none, got %d", name, got)
+			}
+			return
+		}
+		if !ok || got != all[idx] {
+			t.Fatalf("%s: expected %d, got %d(%v)", name, all[idx], got, ok)
+		}
+	}
+	for v := int64(-1); v <= num*4+1; v++ {
+		ceil := sort.Search(len(all), func(i int) bool { return all[i] >= v })
+		higher := sort.Search(len(all), func(i int) bool { return all[i] > v })
+		got, ok := s.Ceiling(v)
+		check("ceiling", got, ok, ceil)
+		got, ok = s.Higher(v)
+		check("higher", got, ok, higher)
+		got, ok = s.Floor(v)
+		check("floor", got, ok, higher-1)
+		got, ok = s.Lower(v)
+		check("lower", got, ok, ceil-1)
+	}
+
+	d := NewDesc[int64]()
+	for _, v := range []int64{10, 20, 30} {
+		d.Add(v)
+	}
+	if v, _ := d.Ceiling(25); v != 20 {
+		t.Fatal("invalid ceiling", v)
+	}
+	if v, _ := d.Floor(25); v != 30 {
+		t.Fatal("invalid floor", v)
+	}
+	if v, _ := d.Higher(20); v != 10 {
+		t.Fatal("invalid higher", v)
+	}
+	if v, _ := d.Lower(20); v != 30 {
+		t.Fatal("invalid lower", v)
+	}
+
+	// The even values are never removed, so the answers must stay the same
+	// while others keep adding and removing the odd values.
+	s = NewInt64()
+	for i := int64(0); i <= 100; i += 2 {
+		s.Add(i)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < 1000; j++ {
+				v := int64(fastrand.Uint32n(50))*2 + 1
+				s.Add(v)
+				s.Remove(v)
+			}
+			wg.Done()
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		if v, _ := s.Lower(51); v != 50 && v != 49 {
+			t.Fatal("invalid lower", v)
+		}
+		if v, _ := s.Higher(49); v != 50 && v != 51 {
+			t.Fatal("invalid higher", v)
+		}
+		if v, _ := s.Floor(50); v != 50 {
+			t.Fatal("invalid floor", v)
+		}
+		if v, _ := s.Ceiling(50); v != 50 {
+			t.Fatal("invalid ceiling", v)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,384 @@
+package skipset
+
+import (
+	"cmp"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// spanIndex maintains the span counters used by the order statistics, such as Rank and Select.
+//
+// The span of a node at level i is the number of nodes from it to its next node at level i, or
+// to the end of the skip list if there is no next node. The header's rank is 0 and the nodes are
+// ranked from 1, so the rank of a node is the sum of the spans along the search path to it.
+//
+// Only the skip sets created by NewIndexed and its variants are indexed. The writers of them update
+// the spans and modify the skip list while holding mu, and readers use seq to get a consistent view.
+type spanIndex struct {
+	indexed bool // set by the constructor and never changed
+	mu      sync.Mutex
+	seq     uint64 // odd if a writer is modifying the skip list
+}
+
+// indexEntry is the index of a node at one level.
+type indexEntry[T any] struct {
+	span int64 // accessed atomically
+	sum  T     // the sum of the values in the span, only maintained by SumSet, protected by spanIndex.mu
+}
+
+// maxSpanReadRetry is the number of optimistic reads before the reader falls back to locking.
+const maxSpanReadRetry = 3
+
+func newIndex[T any](level int) *indexEntry[T] {
+	return &make([]indexEntry[T], level)[0]
+}
+
+func (n *node[T]) indexAt(i int) *indexEntry[T] {
+	return (*indexEntry[T])(unsafe.Add(unsafe.Pointer(n.index), uintptr(i)*unsafe.Sizeof(*n.index)))
+}
+
+func (n *node[T]) loadSpan(i int) int64 {
+	return atomic.LoadInt64(&n.indexAt(i).span)
+}
+
+func (n *node[T]) storeSpan(i int, span int64) {
+	atomic.StoreInt64(&n.indexAt(i).span, span)
+}
+
+// beginUpdate must be called before a writer modifies the skip list, it returns true if the
+// skip set is indexed, then the writer must update the index before calling endUpdate.
+func (s *Set[T]) beginUpdate() bool {
+	if !s.index.indexed {
+		return false
+	}
+	s.index.mu.Lock()
+	atomic.AddUint64(&s.index.seq, 1)
+	return true
+}
+
+func (s *Set[T]) endUpdate(indexed bool) {
+	if indexed {
+		atomic.AddUint64(&s.index.seq, 1)
+		s.index.mu.Unlock()
+	}
+}
+
+// findIndexPreds searches the last nodes in l whose values are less than value in all levels,
+// the ranks of them are stored in ranks. It must be called with the index.mu held.
+func (s *Set[T]) findIndexPreds(l *list[T], value T, preds *[maxLevel]*node[T], ranks *[maxLevel]int64) {
+	var (
+		x    = l.header
+		rank int64
+	)
+	for i := maxLevel - 1; i >= 0; i-- {
+		nex := x.loadNext(i)
+		for nex != nil && s.less(nex.value, value) {
+			rank += x.loadSpan(i)
+			x = nex
+			nex = x.loadNext(i)
+		}
+		preds[i] = x
+		ranks[i] = rank
+	}
+}
+
+// indexAdd updates the index and links the new node, preds and succs are the neighbors
+// of the node in its levels.
+func (s *Set[T]) indexAdd(l *list[T], nn *node[T], preds, succs *[maxLevel]*node[T]) {
+	var (
+		ipreds [maxLevel]*node[T]
+		ranks  [maxLevel]int64
+	)
+	s.findIndexPreds(l, nn.value, &ipreds, &ranks)
+	nn.index = newIndex[T](int(nn.level))
+	rank := ranks[0] + 1
+	for i := 0; i < maxLevel; i++ {
+		span := ipreds[i].loadSpan(i)
+		if i < int(nn.level) {
+			// The new node splits the span of its previous node.
+			ipreds[i].storeSpan(i, rank-ranks[i])
+			nn.storeSpan(i, ranks[i]+span+1-rank)
+		} else {
+			ipreds[i].storeSpan(i, span+1)
+		}
+	}
+	nn.link(preds, succs)
+	if s.agg != nil {
+		for i := 0; i < maxLevel; i++ {
+			s.updateSum(ipreds[i], i)
+			if i < int(nn.level) {
+				s.updateSum(nn, i)
+			}
+		}
+	}
+}
+
+// indexRemove updates the index and unlinks the node, preds are the previous nodes
+// of the node in its levels.
+func (s *Set[T]) indexRemove(l *list[T], n *node[T], preds *[maxLevel]*node[T]) {
+	var (
+		ipreds [maxLevel]*node[T]
+		ranks  [maxLevel]int64
+	)
+	s.findIndexPreds(l, n.value, &ipreds, &ranks)
+	for i := 0; i < maxLevel; i++ {
+		span := ipreds[i].loadSpan(i) - 1
+		if i < int(n.level) {
+			// The previous node takes over the span of the removed node.
+			span += n.loadSpan(i)
+		}
+		ipreds[i].storeSpan(i, span)
+	}
+	n.unlink(preds)
+	if s.agg != nil {
+		for i := 0; i < maxLevel; i++ {
+			s.updateSum(ipreds[i], i)
+		}
+	}
+}
+
+// updateSum recomputes the sum of the span of x in level i from the sums of the spans in
+// level i-1, which must be up to date. The sums are never subtracted, since a float sum can
+// not be restored by subtraction (e.g. 1e17+1-1e17 is 0 and +Inf-+Inf is NaN), so a span sum
+// only depends on the values in the span. It must be called with the index.mu held.
+func (s *Set[T]) updateSum(x *node[T], i int) {
+	var sum T
+	if i == 0 {
+		if nex := x.loadNext(0); nex != nil {
+			sum = nex.value
+		}
+	} else {
+		end := x.loadNext(i)
+		for y := x; y != end; y = y.loadNext(i - 1) {
+			sum = s.agg.add(sum, y.indexAt(i-1).sum)
+		}
+	}
+	x.indexAt(i).sum = sum
+}
+
+// NewIndexed return an empty skip set in ascending order which maintains the index for the order
+// statistics, such as Rank and Select.
+//
+// The order statistics of an indexed skip set cost O(log n) instead of O(n), but its writers have
+// to update the index while holding a mutex, so they are serialized while linking or unlinking
+// the nodes and Add and Remove will be slower under contention.
+func NewIndexed[T cmp.Ordered]() *Set[T] {
+	s := New[T]()
+	s.buildIndex()
+	return s
+}
+
+// NewIndexedDesc return an empty skip set in descending order which maintains the index for
+// the order statistics. See NewIndexed.
+func NewIndexedDesc[T cmp.Ordered]() *Set[T] {
+	s := NewDesc[T]()
+	s.buildIndex()
+	return s
+}
+
+// NewIndexedFunc return an empty skip set ordered by the less function which maintains the index
+// for the order statistics. See NewIndexed and NewFunc.
+func NewIndexedFunc[T any](less func(a, b T) bool) *Set[T] {
+	s := NewFunc(less)
+	s.buildIndex()
+	return s
+}
+
+// buildIndex builds the index for all the nodes, the skip set must be invisible to other goroutines.
+func (s *Set[T]) buildIndex() {
+	l := s.load()
+	for x := l.header; x != nil; x = x.loadNext(0) {
+		x.index = newIndex[T](int(x.level))
+		x.storeSpan(0, 1)
+		if nex := x.loadNext(0); nex != nil && s.agg != nil {
+			x.indexAt(0).sum = nex.value
+		}
+	}
+	for i := 1; i < maxLevel; i++ {
+		// Sum the spans of the level below between two nodes at this level.
+		var (
+			owner = l.header
+			span  int64
+			sum   T
+		)
+		for x := l.header; ; {
+			span += x.loadSpan(i - 1)
+			if s.agg != nil {
+				sum = s.agg.add(sum, x.indexAt(i-1).sum)
+			}
+			nex := x.loadNext(i - 1)
+			if nex == nil || int(nex.level) > i {
+				owner.storeSpan(i, span)
+				owner.indexAt(i).sum = sum
+				if nex == nil {
+					break
+				}
+				owner, span, sum = nex, 0, *new(T)
+			}
+			x = nex
+		}
+	}
+	s.index.indexed = true
+}
+
+// readIndex calls f with a consistent view of the spans, f may be called multiple times.
+// If the skip set is not indexed, f is called once and the order statistics walk the level 0.
+func (s *Set[T]) readIndex(f func()) {
+	if !s.index.indexed {
+		f()
+		return
+	}
+	for i := 0; i < maxSpanReadRetry; i++ {
+		seq := atomic.LoadUint64(&s.index.seq)
+		if seq&1 == 0 {
+			f()
+			if atomic.LoadUint64(&s.index.seq) == seq {
+				return
+			}
+		}
+	}
+	// Too many concurrent writers, block them to get a consistent view.
+	s.index.mu.Lock()
+	f()
+	s.index.mu.Unlock()
+}
+
+// rank returns the number of nodes in l whose values satisfy before, the values satisfying before
+// must be the smallest ones. It must be called via readIndex.
+func (s *Set[T]) rank(l *list[T], before func(value T) bool) int {
+	if !s.index.indexed {
+		var rank int
+		for x := s.findFirstValid(l.header.atomicLoadNext(0)); x != nil && before(x.value); x = s.findFirstValid(x.atomicLoadNext(0)) {
+			rank++
+		}
+		return rank
+	}
+	x, rank := l.header, int64(0)
+	for i := maxLevel - 1; i >= 0; i-- {
+		nex := x.atomicLoadNext(i)
+		for nex != nil && before(nex.value) {
+			rank += x.loadSpan(i)
+			x = nex
+			nex = x.atomicLoadNext(i)
+		}
+	}
+	return int(rank)
+}
+
+// Rank returns the number of values in the skip set that are less than the given value,
+// which is also the index of the value in the skip set if it is present.
+//
+// The order statistics (Rank, Select, CountRange, Quantile and Median) cost O(log n) via the span
+// counters in the nodes if the skip set is created by NewIndexed or its variants, the results
+// reflect the skip set at an instant during the call, a value that is being removed concurrently
+// may still be counted. Otherwise they walk the level 0 in O(n), and the results are not a
+// consistent view if the skip set is being modified concurrently, like Range.
+func (s *Set[T]) Rank(value T) int {
+	var rank int
+	s.readIndex(func() {
+		rank = s.rank(s.load(), func(v T) bool { return s.less(v, value) })
+	})
+	return rank
+}
+
+// Select returns the value at index k (0-based) of the skip set, ok is false if k is out of range.
+// See Rank for the cost and consistency of the order statistics.
+func (s *Set[T]) Select(k int) (value T, ok bool) {
+	s.readIndex(func() {
+		value, ok = s.selectValue(s.load(), k)
+	})
+	return value, ok
+}
+
+func (s *Set[T]) selectValue(l *list[T], k int) (value T, ok bool) {
+	if k < 0 {
+		return value, false
+	}
+	if !s.index.indexed {
+		x := s.findFirstValid(l.header.atomicLoadNext(0))
+		for ; x != nil && k > 0; k-- {
+			x = s.findFirstValid(x.atomicLoadNext(0))
+		}
+		return nodeValue(x)
+	}
+	target := int64(k) + 1 // rank of the node
+	x, rank := l.header, int64(0)
+	for i := maxLevel - 1; i >= 0; i-- {
+		nex := x.atomicLoadNext(i)
+		for nex != nil && rank+x.loadSpan(i) <= target {
+			rank += x.loadSpan(i)
+			x = nex
+			nex = x.atomicLoadNext(i)
+		}
+		if rank == target {
+			return x.value, true
+		}
+	}
+	return value, false
+}
+
+// CountRange returns the number of values in the skip set between lo and hi, the bounds decide
+// whether lo and hi are included, e.g. CountRange(lo, hi, Closed) counts lo <= v <= hi.
+// See Rank for the cost and consistency of the order statistics.
+func (s *Set[T]) CountRange(lo, hi T, bounds Bounds) int {
+	var count int
+	s.readIndex(func() {
+		l := s.load()
+		count = s.rank(l, func(v T) bool { return !s.afterHi(v, hi, bounds) }) -
+			s.rank(l, func(v T) bool { return s.beforeLo(v, lo, bounds) })
+	})
+	if count < 0 {
+		return 0
+	}
+	return count
+}
+
+// Quantile returns the q-quantile of the skip set by the lower method, which is the value at
+// index floor(q*(n-1)) (0-based) where n is the number of values, i.e. the lower one of the two
+// values around the position q*(n-1) without interpolation. So Quantile(0) is the smallest value
+// and Quantile(1) is the largest value. Note that it differs from the nearest-rank method, whose
+// result is at index ceil(q*n)-1, e.g. the 0.95-quantile of 10 values is at index 8 instead of 9.
+// ok is false if the skip set is empty or q is not in [0, 1].
+//
+// The result of an indexed skip set is exact for the skip set at an instant during the call,
+// even if other goroutines are modifying it. See Rank for the cost and consistency of the order
+// statistics.
+func (s *Set[T]) Quantile(q float64) (value T, ok bool) {
+	if !(q >= 0 && q <= 1) {
+		return value, false
+	}
+	s.readIndex(func() {
+		l := s.load()
+		value, ok = s.selectValue(l, int(q*float64(s.count(l)-1)))
+	})
+	return value, ok
+}
+
+// Median returns the median of the skip set, it is the lower one of the two middle values
+// if the number of values is even. ok is false if the skip set is empty.
+// See Quantile for more details.
+func (s *Set[T]) Median() (value T, ok bool) {
+	s.readIndex(func() {
+		l := s.load()
+		value, ok = s.selectValue(l, (s.count(l)-1)/2)
+	})
+	return value, ok
+}
+
+// count returns the number of nodes in l, it must be called via readIndex.
+func (s *Set[T]) count(l *list[T]) int {
+	if !s.index.indexed {
+		var n int
+		s.rangeFrom(l.header.atomicLoadNext(0), func(T) bool {
+			n++
+			return true
+		})
+		return n
+	}
+	var n int64
+	for x := l.header; x != nil; x = x.atomicLoadNext(maxLevel - 1) {
+		n += x.loadSpan(maxLevel - 1)
+	}
+	return int(n - 1)
+}
@@ -0,0 +1,235 @@
+package skipset
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+// checkRank checks the order statistics of a quiescent skip set.
+func checkRank(t *testing.T, s *Int64Set) {
+	var all []int64
+	s.Range(func(value int64) bool {
+		all = append(all, value)
+		return true
+	})
+	for i, v := range all {
+		if r := s.Rank(v); r != i {
+			t.Fatalf("invalid rank of %d: expected %d, got %d", v, i, r)
+		}
+		if r := s.Rank(v + 1); r != sort.Search(len(all), func(i int) bool { return all[i] >= v+1 }) {
+			t.Fatalf("invalid rank of %d: got %d", v+1, r)
+		}
+		if got, ok := s.Select(i); !ok || got != v {
+			t.Fatalf("invalid select %d: expected %d, got %d", i, v, got)
+		}
+	}
+	if _, ok := s.Select(len(all)); ok {
+		t.Fatal("invalid select")
+	}
+	if _, ok := s.Select(-1); ok {
+		t.Fatal("invalid select")
+	}
+	if !s.index.indexed {
+		return
+	}
+	// Check the spans of all levels.
+	for i := 0; i < maxLevel; i++ {
+		var sum int64
+		for x := s.load().header; x != nil; x = x.loadNext(i) {
+			sum += x.loadSpan(i)
+		}
+		if sum != int64(len(all))+1 {
+			t.Fatalf("invalid spans at level %d: %d", i, sum)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	s := NewIndexed[int64]()
+	if s.Rank(10) != 0 || s.CountRange(0, 10, ClosedOpen) != 0 {
+		t.Fatal("invalid empty set")
+	}
+	if _, ok := s.Select(0); ok {
+		t.Fatal("invalid select")
+	}
+	for i := int64(0); i < 1000; i++ {
+		s.Add(int64(fastrand.Uint32n(10000)))
+	}
+	checkRank(t, s)
+
+	s = NewIndexed[int64]()
+	for i := int64(0); i < 1000; i += 2 {
+		s.Add(i)
+	}
+	checkRank(t, s)
+	if c := s.CountRange(10, 20, ClosedOpen); c != 5 {
+		t.Fatal("invalid count range", c)
+	}
+	if c := s.CountRange(-100, 10000, ClosedOpen); c != 500 {
+		t.Fatal("invalid count range", c)
+	}
+	if c := s.CountRange(20, 10, ClosedOpen); c != 0 {
+		t.Fatal("invalid count range", c)
+	}
+	for bounds, expected := range map[Bounds]int{Closed: 6, ClosedOpen: 5, OpenClosed: 5, Open: 4} {
+		if c := s.CountRange(10, 20, bounds); c != expected {
+			t.Fatal("invalid count range", bounds, c)
+		}
+	}
+	if c := s.CountRange(10, 10, Closed); c != 1 {
+		t.Fatal("invalid count range", c)
+	}
+	if c := s.CountRange(10, 10, Open); c != 0 {
+		t.Fatal("invalid count range", c)
+	}
+	for i := int64(0); i < 1000; i += 4 {
+		s.Remove(i)
+	}
+	checkRank(t, s)
+
+	// The counters must be correct after concurrent modifications.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < 2000; j++ {
+				v := int64(fastrand.Uint32n(2000))
+				switch fastrand.Uint32n(3) {
+				case 0:
+					s.Add(v)
+				case 1:
+					s.Remove(v)
+				default:
+					s.PopMin()
+				}
+			}
+			wg.Done()
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		n := s.CountRange(0, 2000, ClosedOpen)
+		if n < 0 || n > 2000 {
+			t.Fatal("invalid count range", n)
+		}
+		if v, ok := s.Select(int(fastrand.Uint32n(100))); ok && (v < 0 || v >= 2000) {
+			t.Fatal("invalid select", v)
+		}
+	}
+	wg.Wait()
+	checkRank(t, s)
+	if s.CountRange(-1, 2000, ClosedOpen) != s.Len() {
+		t.Fatal("invalid count range")
+	}
+
+	// The skip set without index walks the level 0.
+	x := NewInt64()
+	for i := int64(0); i < 1000; i += 3 {
+		x.Add(i)
+	}
+	checkRank(t, x)
+	if c := x.CountRange(10, 20, ClosedOpen); c != 3 {
+		t.Fatal("invalid count range", c)
+	}
+	if c := x.CountRange(9, 18, Closed); c != 4 {
+		t.Fatal("invalid count range", c)
+	}
+	// The largest value of the type can be counted.
+	x.Add(math.MaxInt64)
+	if c := x.CountRange(1000, math.MaxInt64, Closed); c != 1 {
+		t.Fatal("invalid count range", c)
+	}
+	s.Add(math.MaxInt64)
+	if c := s.CountRange(0, math.MaxInt64, Closed); c != s.Len() {
+		t.Fatal("invalid count range", c)
+	}
+	if x.index.indexed || x.load().header.index != nil {
+		t.Fatal("the order statistics must not index the skip set")
+	}
+
+	d := NewIndexedDesc[int64]()
+	for i := int64(0); i < 10; i++ {
+		d.Add(i)
+	}
+	if d.Rank(7) != 2 || d.CountRange(7, 3, ClosedOpen) != 4 {
+		t.Fatal("invalid desc rank")
+	}
+	if v, _ := d.Select(0); v != 9 {
+		t.Fatal("invalid desc select", v)
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	s := NewIndexed[float64]()
+	if _, ok := s.Quantile(0.5); ok {
+		t.Fatal("invalid quantile")
+	}
+	if _, ok := s.Median(); ok {
+		t.Fatal("invalid median")
+	}
+	for i := 1; i <= 100; i++ {
+		s.Add(float64(i))
+	}
+	for _, tc := range []struct {
+		q        float64
+		expected float64
+	}{{0, 1}, {0.5, 50}, {0.9, 90}, {0.99, 99}, {1, 100}} {
+		if v, ok := s.Quantile(tc.q); !ok || v != tc.expected {
+			t.Fatalf("invalid quantile %v: expected %v, got %v", tc.q, tc.expected, v)
+		}
+	}
+	// The lower method, the nearest-rank method would return 10.
+	if v, _ := NewFloat64FromSorted([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}); v == nil {
+		t.Fatal("invalid build")
+	} else if q, _ := v.Quantile(0.95); q != 9 {
+		t.Fatal("invalid quantile", q)
+	}
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, ok := s.Quantile(q); ok {
+			t.Fatal("invalid quantile", q)
+		}
+	}
+	if v, _ := s.Median(); v != 50 {
+		t.Fatal("invalid median", v)
+	}
+	s.Add(101)
+	if v, _ := s.Median(); v != 51 {
+		t.Fatal("invalid median", v)
+	}
+	if v, _ := NewFloat64FromSorted([]float64{1, 2, 3, 4}); v == nil {
+		t.Fatal("invalid build")
+	} else if m, _ := v.Median(); m != 2 {
+		t.Fatal("invalid median without index", m)
+	}
+
+	// The values in [1000, 2000) are added and removed concurrently,
+	// the median must be in the set of the fixed values or the changing values.
+	x := NewIndexed[int64]()
+	for i := int64(0); i < 1000; i++ {
+		x.Add(i)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < 2000; j++ {
+				v := int64(fastrand.Uint32n(1000)) + 1000
+				x.Add(v)
+				x.Remove(v)
+			}
+			wg.Done()
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		if v, ok := x.Median(); !ok || v < 499 || v >= 2000 {
+			t.Fatal("invalid median", v)
+		}
+	}
+	wg.Wait()
+	if v, _ := x.Median(); v != 499 {
+		t.Fatal("invalid median", v)
+	}
+}
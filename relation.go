@@ -0,0 +1,91 @@
+package skipset
+
+// The relation methods walk the two skip sets at the same time and stop at the first difference,
+// the two skip sets must have the same order, and they panic if the first two values of either
+// skip set are out of the order of the other one. Like Range, they are not a consistent view if
+// the skip sets are being modified concurrently, use Snapshot if it is required.
+
+// Equal reports whether the skip set and other contain the same values.
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	checkOrders(s, other)
+	x := s.findFirstValid(s.load().header.atomicLoadNext(0))
+	y := other.findFirstValid(other.load().header.atomicLoadNext(0))
+	for x != nil && y != nil {
+		if s.less(x.value, y.value) || s.less(y.value, x.value) {
+			return false
+		}
+		x = s.findFirstValid(x.atomicLoadNext(0))
+		y = other.findFirstValid(y.atomicLoadNext(0))
+	}
+	return x == nil && y == nil
+}
+
+// IsSubsetOf reports whether every value in the skip set is also in other.
+//
+// Each value of the skip set is searched in other from the search result of the previous value,
+// so the long runs of other between two values are skipped via the towers.
+func (s *Set[T]) IsSubsetOf(other *Set[T]) bool {
+	checkOrders(s, other)
+	k := newSeeker(other)
+	for x := s.findFirstValid(s.load().header.atomicLoadNext(0)); x != nil; x = s.findFirstValid(x.atomicLoadNext(0)) {
+		y := k.seek(x.value)
+		if y == nil || s.less(x.value, y.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSupersetOf reports whether every value in other is also in the skip set. See IsSubsetOf.
+func (s *Set[T]) IsSupersetOf(other *Set[T]) bool {
+	return other.IsSubsetOf(s)
+}
+
+// IsDisjoint reports whether the skip set and other have no value in common.
+//
+// The two skip sets seek the current value of each other in turn, so the long runs in both
+// of them are skipped via the towers.
+func (s *Set[T]) IsDisjoint(other *Set[T]) bool {
+	checkOrders(s, other)
+	ks, ko := newSeeker(s), newSeeker(other)
+	x := s.findFirstValid(s.load().header.atomicLoadNext(0))
+	for x != nil {
+		y := ko.seek(x.value)
+		if y == nil {
+			return true
+		}
+		if !s.less(x.value, y.value) {
+			return false
+		}
+		x = ks.seek(y.value)
+	}
+	return true
+}
+
+// seeker searches the values in a skip set in ascending order, each search starts from the
+// result of the previous one if the value is greater and the skip set is not cleared since then,
+// see findNodeFinger.
+type seeker[T any] struct {
+	s            *Set[T]
+	l            *list[T] // the skip list of the previous search
+	preds, succs [maxLevel]*node[T]
+	last         T
+	started      bool
+}
+
+func newSeeker[T any](s *Set[T]) *seeker[T] {
+	return &seeker[T]{s: s}
+}
+
+// seek returns the first fully linked and unmarked node whose value is not less than value,
+// or nil if there is no such node. It never blocks like Contains.
+func (k *seeker[T]) seek(value T) *node[T] {
+	l := k.s.load()
+	if k.started && k.l == l && k.s.less(k.last, value) {
+		k.s.findNodeFinger(l, value, &k.preds, &k.succs)
+	} else {
+		k.s.findNodeRemove(l, value, &k.preds, &k.succs)
+	}
+	k.l, k.last, k.started = l, value, true
+	return k.s.findFirstValid(k.succs[0])
+}
@@ -0,0 +1,133 @@
+package skipset
+
+import (
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestSetRelation(t *testing.T) {
+	empty := NewInt64()
+	if !empty.Equal(NewInt64()) || !empty.IsSubsetOf(NewInt64()) || !empty.IsDisjoint(NewInt64()) {
+		t.Fatal("invalid empty relation")
+	}
+	for i := 0; i < 50; i++ {
+		a, b := NewInt64(), NewInt64()
+		inA, inB := make(map[int64]bool), make(map[int64]bool)
+		n := fastrand.Uint32n(300)
+		for j := uint32(0); j < n; j++ {
+			v := int64(fastrand.Uint32n(2000))
+			a.Add(v)
+			inA[v] = true
+		}
+		switch i % 4 {
+		case 0: // subset
+			a.Range(func(v int64) bool {
+				if fastrand.Uint32n(4) != 0 {
+					b.Add(v)
+					inB[v] = true
+				}
+				return true
+			})
+		case 1: // superset
+			a.Range(func(v int64) bool {
+				b.Add(v)
+				inB[v] = true
+				return true
+			})
+			for j := uint32(0); j < n; j++ {
+				v := int64(fastrand.Uint32n(2000))
+				b.Add(v)
+				inB[v] = true
+			}
+		case 2: // equal
+			a.Range(func(v int64) bool {
+				b.Add(v)
+				inB[v] = true
+				return true
+			})
+		default: // random, mostly disjoint
+			for j := uint32(0); j < n; j++ {
+				v := int64(fastrand.Uint32n(2000)) + 1900
+				b.Add(v)
+				inB[v] = true
+			}
+		}
+		var (
+			subset   = true
+			superset = true
+			disjoint = true
+		)
+		for v := range inA {
+			if !inB[v] {
+				subset = false
+			} else {
+				disjoint = false
+			}
+		}
+		for v := range inB {
+			if !inA[v] {
+				superset = false
+			}
+		}
+		equal := subset && superset
+		if a.Equal(b) != equal || b.Equal(a) != equal {
+			t.Fatal("invalid equal")
+		}
+		if a.IsSubsetOf(b) != subset || b.IsSupersetOf(a) != subset {
+			t.Fatal("invalid subset")
+		}
+		if a.IsSupersetOf(b) != superset || b.IsSubsetOf(a) != superset {
+			t.Fatal("invalid superset")
+		}
+		if a.IsDisjoint(b) != disjoint || b.IsDisjoint(a) != disjoint {
+			t.Fatal("invalid disjoint")
+		}
+	}
+
+	// The skip sets with different orders are rejected instead of returning a wrong result.
+	asc, desc, one := NewInt(), NewIntDesc(), NewInt()
+	asc.AddBatch([]int{1, 2})
+	desc.AddBatch([]int{1, 2})
+	one.Add(5)
+	for name, f := range map[string]func(){
+		"equal":     func() { asc.Equal(desc) },
+		"subset":    func() { one.IsSubsetOf(desc) },
+		"superset":  func() { desc.IsSupersetOf(one) },
+		"disjoint":  func() { one.IsDisjoint(desc) },
+		"disjoint2": func() { desc.IsDisjoint(one) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != orderMismatch {
+					t.Fatal("invalid panic of "+name, r)
+				}
+			}()
+			f()
+		}()
+	}
+	// The order does not matter if both skip sets have less than two values.
+	desc.Clear()
+	desc.Add(5)
+	if !desc.Equal(one) || !desc.IsSubsetOf(one) || desc.IsDisjoint(one) {
+		t.Fatal("invalid relation of single values")
+	}
+}
+
+func TestStringSetRelation(t *testing.T) {
+	a, b := NewString(), NewString()
+	a.AddBatch([]string{"a", "b"})
+	b.AddBatch([]string{"b", "a", "c"})
+	if a.Equal(b) || !a.IsSubsetOf(b) || !b.IsSupersetOf(a) || a.IsDisjoint(b) {
+		t.Fatal("invalid relation")
+	}
+	a.Add("c")
+	if !a.Equal(b) {
+		t.Fatal("invalid equal")
+	}
+	b.Clear()
+	b.Add("d")
+	if !a.IsDisjoint(b) || a.IsSubsetOf(b) {
+		t.Fatal("invalid disjoint")
+	}
+}
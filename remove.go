@@ -0,0 +1,66 @@
+package skipset
+
+// RemoveRange removes all the values between lo and hi from the skip set, the bounds decide
+// whether lo and hi are included, see RangeBetween. It returns the number of values removed by this call.
+//
+// The range is walked once in level 0, each node is removed with the preds of the previous
+// removed node as the finger, so it costs much less than calling Remove for each value.
+func (s *Set[T]) RemoveRange(lo, hi T, bounds Bounds) int {
+	l := s.load()
+	x := s.findLast(l, func(v T) bool { return s.beforeLo(v, lo, bounds) })
+	return s.removeFunc(l, x.atomicLoadNext(0), func(v T) bool {
+		return s.afterHi(v, hi, bounds)
+	}, func(T) bool {
+		return true
+	})
+}
+
+// RemoveIf removes all the values for which f returns true from the skip set,
+// it returns the number of values removed by this call.
+// The values are passed to f in order, and no lock is held while f runs, so f may call
+// the methods of the skip set, such as Rank and Snapshot.
+func (s *Set[T]) RemoveIf(f func(value T) bool) int {
+	l := s.load()
+	return s.removeFunc(l, l.header.atomicLoadNext(0), func(T) bool {
+		return false
+	}, f)
+}
+
+// Retain removes all the values for which f returns false from the skip set,
+// it returns the number of values removed by this call. See RemoveIf.
+func (s *Set[T]) Retain(f func(value T) bool) int {
+	return s.RemoveIf(func(value T) bool {
+		return !f(value)
+	})
+}
+
+// removeFunc walks the skip list l in level 0 starting from x and removes each node for which
+// f returns true, it stops at the first node for which stop returns true. The walk also stops
+// if the skip set is cleared, the values after that are added during the call.
+func (s *Set[T]) removeFunc(l *list[T], x *node[T], stop, f func(value T) bool) int {
+	var (
+		preds, succs [maxLevel]*node[T]
+		count        int
+	)
+	for ; x != nil && s.load() == l; x = x.atomicLoadNext(0) {
+		if !s.present(x) {
+			continue
+		}
+		if stop(x.value) {
+			break
+		}
+		// The node is skipped if it is removed by another process.
+		if !f(x.value) || !s.mark(l, x) {
+			continue
+		}
+		// The next pointers of a marked node are never changed, so the walk can go on from it.
+		if count > 0 {
+			s.findNodeFinger(l, x.value, &preds, &succs)
+		} else {
+			s.findNodeRemove(l, x.value, &preds, &succs)
+		}
+		s.unlink(l, x, &preds, &succs)
+		count++
+	}
+	return count
+}
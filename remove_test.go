@@ -0,0 +1,132 @@
+package skipset
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestRemoveRange(t *testing.T) {
+	s := NewIndexed[int64]()
+	if s.RemoveRange(0, 10, ClosedOpen) != 0 || s.RemoveIf(func(int64) bool { return true }) != 0 {
+		t.Fatal("invalid empty remove")
+	}
+	for i := int64(0); i < 1000; i++ {
+		s.Add(i)
+	}
+	if n := s.RemoveRange(100, 200, ClosedOpen); n != 100 || s.Len() != 900 {
+		t.Fatal("invalid remove range", n)
+	}
+	if s.Contains(100) || s.Contains(199) || !s.Contains(99) || !s.Contains(200) {
+		t.Fatal("invalid remove range")
+	}
+	if n := s.RemoveRange(300, 300, ClosedOpen); n != 0 {
+		t.Fatal("invalid remove range", n)
+	}
+	if n := s.RemoveRange(300, 300, Closed); n != 1 || s.Contains(300) {
+		t.Fatal("invalid remove range", n)
+	}
+	s.Add(300)
+	if n := s.RemoveIf(func(v int64) bool { return v%2 == 1 }); n != 450 || s.Len() != 450 {
+		t.Fatal("invalid remove if", n)
+	}
+	if n := s.Retain(func(v int64) bool { return v < 500 }); n != 250 || s.Len() != 200 {
+		t.Fatal("invalid retain", n)
+	}
+	var expected []int64
+	for i := int64(0); i < 500; i += 2 {
+		if i < 100 || i >= 200 {
+			expected = append(expected, i)
+		}
+	}
+	if !slices.Equal(slices.Collect(s.All()), expected) {
+		t.Fatal("invalid values")
+	}
+	// The index is maintained.
+	checkRank(t, s)
+	if n := s.RemoveRange(-1, 1000, ClosedOpen); n != 200 || s.Len() != 0 {
+		t.Fatal("invalid remove range", n)
+	}
+	checkRank(t, s)
+
+	// Each value is removed exactly once by the concurrent removers.
+	for i := int64(0); i < 10000; i++ {
+		s.Add(i)
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		removed int
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			var n int
+			switch i % 3 {
+			case 0:
+				n = s.RemoveRange(int64(fastrand.Uint32n(5000)), 10000, ClosedOpen)
+			case 1:
+				n = s.RemoveIf(func(v int64) bool { return v%3 == 0 })
+			default:
+				for j := int64(0); j < 10000; j += 7 {
+					if s.Remove(j) {
+						n++
+					}
+				}
+			}
+			mu.Lock()
+			removed += n
+			mu.Unlock()
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+	if removed+s.Len() != 10000 {
+		t.Fatal("invalid removed count", removed, s.Len())
+	}
+	checkRank(t, s)
+}
+
+func TestStringSetRemoveIf(t *testing.T) {
+	s := NewString()
+	s.AddBatch([]string{"a", "bb", "ccc", "dd", "e"})
+	if n := s.RemoveIf(func(v string) bool { return len(v) == 2 }); n != 2 || s.Len() != 3 {
+		t.Fatal("invalid remove if", n)
+	}
+	if n := s.Retain(func(v string) bool { return v == "a" }); n != 2 || s.Len() != 1 || !s.Contains("a") {
+		t.Fatal("invalid retain", n)
+	}
+}
+
+func TestRemoveIfReentrant(t *testing.T) {
+	s := NewIndexed[int64]()
+	for i := int64(0); i < 100; i++ {
+		s.Add(i)
+	}
+	// The predicate may call the methods of the skip set, no lock is held while it runs.
+	n := s.RemoveIf(func(v int64) bool {
+		if s.Rank(v) < 0 || s.Snapshot().Len() == 0 || s.Clone().Len() == 0 {
+			t.Fatal("invalid set")
+		}
+		if v < 100 {
+			s.Add(v + 1000) // visited later by the walk
+		}
+		return v%2 == 0
+	})
+	if n != 100 || s.Len() != 100 {
+		t.Fatal("invalid remove if", n, s.Len())
+	}
+	// The walk stops if the skip set is cleared by the predicate.
+	n = s.RemoveIf(func(v int64) bool {
+		if v == 11 {
+			s.Clear()
+		}
+		return true
+	})
+	if n != 6 || s.Len() != 0 {
+		t.Fatal("invalid remove if", n, s.Len())
+	}
+	checkRank(t, s)
+}
@@ -4,79 +4,199 @@
 package skipset
 
 import (
+	"cmp"
+	"context"
 	"sync"
 	"sync/atomic"
 	"unsafe"
 )
 
-// Int64Set represents a set based on skip list in ascending order.
-type Int64Set struct {
-	header       *int64Node
-	length       int64
-	highestLevel int64 // highest level for now
+// Set represents a set based on skip list.
+//
+// The order of the values is decided by the set's less function, see New, NewDesc and NewFunc.
+// The words such as smallest, largest, less and greater in the docs all follow this order,
+// e.g. the Min of a descending set is its largest number.
+type Set[T any] struct {
+	cur          atomic.Pointer[list[T]] // replaced by Clear
+	highestLevel int64                   // highest level for now
+	less         func(a, b T) bool
+	clock        uint64   // the timestamps of the additions and removals, increased by Snapshot
+	added        notifier // notified after a value is added
+	index        spanIndex
+	agg          *aggregator[T] // maintains the sums in the index if not nil, see SumSet
+	ordered      searcher[T]    // the searching functions without calling less, nil if created by NewFunc
+}
+
+// list is the skip list of a skip set. Every operation loads the list once and works on it,
+// so Clear can replace the list with an empty one without waiting for the writers.
+type list[T any] struct {
+	header     *node[T]
+	length     int64
+	snapshots  snapshotList[T]
+	versioned  uint32    // whether the new nodes are versioned, accessed atomically, see version
+	versioning sync.Once // enables the versioning when the list is snapshotted for the first time
 }
 
-type int64Node struct {
-	value int64
-	next  optionalArray // [level]*int64Node
+type node[T any] struct {
+	value T
+	next  optionalArray // [level]*node[T]
 	mu    sync.Mutex
 	flags bitflag
 	level uint32
+	index *indexEntry[T] // [level]indexEntry[T], nil if the skip set is not indexed, see spanIndex
+	// The timestamps of the node for Snapshot, nil if the node is added before the list is versioned.
+	ver atomic.Pointer[version]
 }
 
-func newInt64Node(value int64, level int) *int64Node {
-	node := &int64Node{
+func newNode[T any](value T, level int) *node[T] {
+	n := &node[T]{
 		value: value,
 		level: uint32(level),
 	}
 	if level > op1 {
-		node.next.extra = new([op2]unsafe.Pointer)
+		n.next.extra = new([op2]unsafe.Pointer)
 	}
-	return node
+	return n
 }
 
-func (n *int64Node) loadNext(i int) *int64Node {
-	return (*int64Node)(n.next.load(i))
+func (n *node[T]) loadNext(i int) *node[T] {
+	return (*node[T])(n.next.load(i))
 }
 
-func (n *int64Node) storeNext(i int, node *int64Node) {
-	n.next.store(i, unsafe.Pointer(node))
+func (n *node[T]) storeNext(i int, next *node[T]) {
+	n.next.store(i, unsafe.Pointer(next))
 }
 
-func (n *int64Node) atomicLoadNext(i int) *int64Node {
-	return (*int64Node)(n.next.atomicLoad(i))
+func (n *node[T]) atomicLoadNext(i int) *node[T] {
+	return (*node[T])(n.next.atomicLoad(i))
 }
 
-func (n *int64Node) atomicStoreNext(i int, node *int64Node) {
-	n.next.atomicStore(i, unsafe.Pointer(node))
+func (n *node[T]) atomicStoreNext(i int, next *node[T]) {
+	n.next.atomicStore(i, unsafe.Pointer(next))
 }
 
-func (n *int64Node) lessthan(value int64) bool {
-	return n.value < value
+// link links the new node between preds and succs in all its levels.
+func (n *node[T]) link(preds, succs *[maxLevel]*node[T]) {
+	for layer := 0; layer < int(n.level); layer++ {
+		n.storeNext(layer, succs[layer])
+		preds[layer].atomicStoreNext(layer, n)
+	}
 }
 
-func (n *int64Node) equal(value int64) bool {
-	return n.value == value
+// unlink unlinks the node from preds in all its levels, the caller must own the node.
+func (n *node[T]) unlink(preds *[maxLevel]*node[T]) {
+	for i := int(n.level) - 1; i >= 0; i-- {
+		// Now we own the node, no other goroutine will modify it.
+		// So we don't need `n.loadNext`
+		preds[i].atomicStoreNext(i, n.loadNext(i))
+	}
 }
 
-// NewInt64 return an empty int64 skip set in ascending order.
-func NewInt64() *Int64Set {
-	h := newInt64Node(0, maxLevel)
-	h.flags.SetTrue(fullyLinked)
-	return &Int64Set{
-		header:       h,
+// New return an empty skip set in ascending order.
+func New[T cmp.Ordered]() *Set[T] {
+	return newSet(cmp.Less[T], orderedSearch[T]{})
+}
+
+// NewDesc return an empty skip set in descending order.
+func NewDesc[T cmp.Ordered]() *Set[T] {
+	less := func(a, b T) bool {
+		return cmp.Less(b, a)
+	}
+	return newSet(less, orderedSearch[T]{desc: true})
+}
+
+// NewFunc return an empty skip set ordered by the less function.
+//
+// The less function must describe a strict weak ordering, two values are
+// treated as the same value if neither of them is less than the other.
+func NewFunc[T any](less func(a, b T) bool) *Set[T] {
+	return newSet(less, nil)
+}
+
+// newSet returns an empty skip set ordered by less, ordered is nil if the order is not
+// the order of New or NewDesc, see searcher.
+func newSet[T any](less func(a, b T) bool, ordered searcher[T]) *Set[T] {
+	s := &Set[T]{
 		highestLevel: defaultHighestLevel,
+		less:         less,
+		clock:        1,
+		ordered:      ordered,
+	}
+	s.cur.Store(s.newList())
+	return s
+}
+
+// newList returns an empty skip list, the header has the index if the skip set is indexed.
+func (s *Set[T]) newList() *list[T] {
+	var zero T
+	h := newNode(zero, maxLevel)
+	h.flags.SetTrue(fullyLinked)
+	if s.index.indexed {
+		h.index = newIndex[T](maxLevel)
+		for i := 0; i < maxLevel; i++ {
+			h.storeSpan(i, 1)
+		}
+	}
+	return &list[T]{header: h}
+}
+
+// load returns the current skip list.
+func (s *Set[T]) load() *list[T] {
+	return s.cur.Load()
+}
+
+// stamp sets the timestamp ts to the clock if it is not set yet, and returns the timestamp.
+//
+// A node is added (or removed) at the instant its timestamp is taken from the clock, so every
+// process that reports the node as present (or absent via its marked flag) stamps it first.
+func (s *Set[T]) stamp(ts *uint64) uint64 {
+	if v := atomic.LoadUint64(ts); v != 0 {
+		return v
+	}
+	atomic.CompareAndSwapUint64(ts, 0, atomic.LoadUint64(&s.clock))
+	return atomic.LoadUint64(ts)
+}
+
+// stampAdded stamps the addition of the node if it is versioned.
+func (s *Set[T]) stampAdded(n *node[T]) {
+	if v := n.ver.Load(); v != nil {
+		s.stamp(&v.added)
+	}
+}
+
+// stampRemoved stamps the removal of the node if it is versioned.
+func (s *Set[T]) stampRemoved(n *node[T]) {
+	if v := n.ver.Load(); v != nil {
+		s.stamp(&v.removed)
+	}
+}
+
+// present reports whether the node is fully linked and unmarked, the node is stamped before
+// it is reported as present or removed, see stamp.
+func (s *Set[T]) present(n *node[T]) bool {
+	if n.flags.MGet(fullyLinked|marked, fullyLinked) {
+		s.stampAdded(n)
+		return true
+	}
+	if n.flags.Get(marked) {
+		s.stampRemoved(n)
 	}
+	return false
 }
 
 // findNodeRemove takes a value and two maximal-height arrays then searches exactly as in a sequential skip-list.
 // The returned preds and succs always satisfy preds[i] > value >= succs[i].
-func (s *Int64Set) findNodeRemove(value int64, preds *[maxLevel]*int64Node, succs *[maxLevel]*int64Node) int {
+func (s *Set[T]) findNodeRemove(l *list[T], value T, preds *[maxLevel]*node[T], succs *[maxLevel]*node[T]) int {
+	top := int(atomic.LoadInt64(&s.highestLevel))
+	if s.ordered != nil {
+		r := s.ordered.findNodeRemove(l.header, top, value)
+		return r.store(top, preds, succs)
+	}
 	// lFound represents the index of the first layer at which it found a node.
-	lFound, x := -1, s.header
-	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+	lFound, x := -1, l.header
+	for i := top - 1; i >= 0; i-- {
 		succ := x.atomicLoadNext(i)
-		for succ != nil && succ.lessthan(value) {
+		for succ != nil && s.less(succ.value, value) {
 			x = succ
 			succ = x.atomicLoadNext(i)
 		}
@@ -84,7 +204,7 @@ func (s *Int64Set) findNodeRemove(value int64, preds *[maxLevel]*int64Node, succ
 		succs[i] = succ
 
 		// Check if the value already in the skip list.
-		if lFound == -1 && succ != nil && succ.equal(value) {
+		if lFound == -1 && succ != nil && !s.less(value, succ.value) {
 			lFound = i
 		}
 	}
@@ -93,11 +213,16 @@ func (s *Int64Set) findNodeRemove(value int64, preds *[maxLevel]*int64Node, succ
 
 // findNodeAdd takes a value and two maximal-height arrays then searches exactly as in a sequential skip-set.
 // The returned preds and succs always satisfy preds[i] > value >= succs[i].
-func (s *Int64Set) findNodeAdd(value int64, preds *[maxLevel]*int64Node, succs *[maxLevel]*int64Node) int {
-	x := s.header
-	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+func (s *Set[T]) findNodeAdd(l *list[T], value T, preds *[maxLevel]*node[T], succs *[maxLevel]*node[T]) int {
+	top := int(atomic.LoadInt64(&s.highestLevel))
+	if s.ordered != nil {
+		r := s.ordered.findNodeAdd(l.header, top, value)
+		return r.store(top, preds, succs)
+	}
+	x := l.header
+	for i := top - 1; i >= 0; i-- {
 		succ := x.atomicLoadNext(i)
-		for succ != nil && succ.lessthan(value) {
+		for succ != nil && s.less(succ.value, value) {
 			x = succ
 			succ = x.atomicLoadNext(i)
 		}
@@ -105,15 +230,15 @@ func (s *Int64Set) findNodeAdd(value int64, preds *[maxLevel]*int64Node, succs *
 		succs[i] = succ
 
 		// Check if the value already in the skip list.
-		if succ != nil && succ.equal(value) {
+		if succ != nil && !s.less(value, succ.value) {
 			return i
 		}
 	}
 	return -1
 }
 
-func unlockInt64(preds [maxLevel]*int64Node, highestLevel int) {
-	var prevPred *int64Node
+func unlock[T any](preds [maxLevel]*node[T], highestLevel int) {
+	var prevPred *node[T]
 	for i := highestLevel; i >= 0; i-- {
 		if preds[i] != prevPred { // the node could be unlocked by previous loop
 			preds[i].mu.Unlock()
@@ -126,28 +251,43 @@ func unlockInt64(preds [maxLevel]*int64Node, highestLevel int) {
 // return false if this process can't insert this value, because another process has insert the same value.
 //
 // If the value is in the skip set but not fully linked, this process will wait until it is.
-func (s *Int64Set) Add(value int64) bool {
+func (s *Set[T]) Add(value T) bool {
+	var preds, succs [maxLevel]*node[T]
+	return s.add(s.load(), value, &preds, &succs, false)
+}
+
+// add adds the value into the skip list l, if finger is true, the search starts from the preds
+// of a smaller value, see findNodeFinger. The preds can be used as the finger of a greater
+// value after add returns.
+func (s *Set[T]) add(l *list[T], value T, preds, succs *[maxLevel]*node[T], finger bool) bool {
 	level := s.randomlevel()
-	var preds, succs [maxLevel]*int64Node
 	for {
-		lFound := s.findNodeAdd(value, &preds, &succs)
+		var lFound int
+		if finger {
+			lFound = s.findNodeFinger(l, value, preds, succs)
+			finger = false // search from the header if we need to retry
+		} else {
+			lFound = s.findNodeAdd(l, value, preds, succs)
+		}
 		if lFound != -1 { // indicating the value is already in the skip-list
 			nodeFound := succs[lFound]
 			if !nodeFound.flags.Get(marked) {
 				for !nodeFound.flags.Get(fullyLinked) {
 					// The node is not yet fully linked, just waits until it is.
 				}
+				s.stampAdded(nodeFound)
 				return false
 			}
 			// If the node is marked, represents some other thread is in the process of deleting this node,
-			// we need to add this node in next loop.
+			// we need to add this node in next loop. The new node must be added after the removal.
+			s.stampRemoved(nodeFound)
 			continue
 		}
 		// Add this node into skip list.
 		var (
 			highestLocked        = -1 // the highest level being locked by this process
 			valid                = true
-			pred, succ, prevPred *int64Node
+			pred, succ, prevPred *node[T]
 		)
 		for layer := 0; valid && layer < level; layer++ {
 			pred = preds[layer]   // target node's previous node
@@ -164,23 +304,35 @@ func (s *Int64Set) Add(value int64) bool {
 			valid = !pred.flags.Get(marked) && (succ == nil || !succ.flags.Get(marked)) && pred.loadNext(layer) == succ
 		}
 		if !valid {
-			unlockInt64(preds, highestLocked)
+			unlock(*preds, highestLocked)
 			continue
 		}
 
-		nn := newInt64Node(value, level)
-		for layer := 0; layer < level; layer++ {
-			nn.storeNext(layer, succs[layer])
-			preds[layer].atomicStoreNext(layer, nn)
+		nn := newNode(value, level)
+		// The flag is read with the preds locked, see enableVersions.
+		if atomic.LoadUint32(&l.versioned) != 0 {
+			nn.ver.Store(new(version))
+		}
+		indexed := s.beginUpdate()
+		if indexed {
+			s.indexAdd(l, nn, preds, succs)
+		} else {
+			nn.link(preds, succs)
 		}
 		nn.flags.SetTrue(fullyLinked)
-		unlockInt64(preds, highestLocked)
-		atomic.AddInt64(&s.length, 1)
+		s.stampAdded(nn)
+		s.endUpdate(indexed)
+		unlock(*preds, highestLocked)
+		for layer := 0; layer < level; layer++ {
+			preds[layer] = nn
+		}
+		atomic.AddInt64(&l.length, 1)
+		s.added.notify()
 		return true
 	}
 }
 
-func (s *Int64Set) randomlevel() int {
+func (s *Set[T]) randomlevel() int {
 	// Generate random level.
 	level := randomLevel()
 	// Update highest level if possible.
@@ -197,92 +349,298 @@ func (s *Int64Set) randomlevel() int {
 }
 
 // Contains check if the value is in the skip set.
-func (s *Int64Set) Contains(value int64) bool {
-	x := s.header
-	for i := int(atomic.LoadInt64(&s.highestLevel)) - 1; i >= 0; i-- {
+func (s *Set[T]) Contains(value T) bool {
+	n := s.findNode(s.load(), value)
+	return n != nil && s.present(n)
+}
+
+// findNode returns the node of the value in the skip list l, or nil if there is no such node.
+// The node may be not fully linked or marked.
+func (s *Set[T]) findNode(l *list[T], value T) *node[T] {
+	x, top := l.header, int(atomic.LoadInt64(&s.highestLevel))
+	if s.ordered != nil {
+		return s.ordered.findNode(x, top, value)
+	}
+	for i := top - 1; i >= 0; i-- {
 		nex := x.atomicLoadNext(i)
-		for nex != nil && nex.lessthan(value) {
+		for nex != nil && s.less(nex.value, value) {
 			x = nex
 			nex = x.atomicLoadNext(i)
 		}
 
 		// Check if the value already in the skip list.
-		if nex != nil && nex.equal(value) {
-			return nex.flags.MGet(fullyLinked|marked, fullyLinked)
+		if nex != nil && !s.less(value, nex.value) {
+			return nex
 		}
 	}
-	return false
+	return nil
 }
 
 // Remove a node from the skip set.
-func (s *Int64Set) Remove(value int64) bool {
-	var (
-		nodeToRemove *int64Node
-		isMarked     bool // represents if this operation mark the node
-		topLayer     = -1
-		preds, succs [maxLevel]*int64Node
-	)
+func (s *Set[T]) Remove(value T) bool {
+	var preds, succs [maxLevel]*node[T]
+	return s.remove(s.load(), value, &preds, &succs, false)
+}
+
+// remove removes the value from the skip list l, if finger is true, the search starts from the preds
+// of a smaller value, see findNodeFinger. The preds can be used as the finger of a greater
+// value after remove returns.
+func (s *Set[T]) remove(l *list[T], value T, preds, succs *[maxLevel]*node[T], finger bool) bool {
+	var lFound int
+	if finger {
+		lFound = s.findNodeFinger(l, value, preds, succs)
+	} else {
+		lFound = s.findNodeRemove(l, value, preds, succs)
+	}
+	// We can find this node in the skip list, and it is fully linked.
+	if lFound == -1 || !s.present(succs[lFound]) || (int(succs[lFound].level)-1) != lFound {
+		return false
+	}
+	nodeToRemove := succs[lFound]
+	if !s.mark(l, nodeToRemove) {
+		return false
+	}
+	s.unlink(l, nodeToRemove, preds, succs)
+	return true
+}
+
+// mark marks the node as logically deleted and keeps the node locked, the caller must accomplish
+// the physical deletion via unlink. It returns false if the node is marked by another process,
+// the physical deletion will be accomplished by another process.
+//
+// The node is stamped as removed after it is marked, and it is recorded by the in-progress
+// snapshots of l before it is unlinked, because they may miss it after that.
+func (s *Set[T]) mark(l *list[T], n *node[T]) bool {
+	n.mu.Lock()
+	if n.flags.Get(marked) {
+		n.mu.Unlock()
+		s.stampRemoved(n)
+		return false
+	}
+	if n.ver.Load() == nil && atomic.LoadUint32(&l.versioned) != 0 {
+		// The node is added before the list is versioned, so it is in all the snapshots.
+		n.ver.Store(&version{added: 1})
+	}
+	s.stampAdded(n) // the node must be added before it is removed
+	n.flags.SetTrue(marked)
+	s.stampRemoved(n)
+	if atomic.LoadInt32(&l.snapshots.active) > 0 {
+		l.snapshots.bury(n)
+	}
+	return true
+}
+
+// unlink accomplishes the physical deletion of the node marked by this process.
+// The preds and succs are the result of findNodeRemove, they will be searched again
+// if the skip list has been changed by another process.
+func (s *Set[T]) unlink(l *list[T], nodeToRemove *node[T], preds, succs *[maxLevel]*node[T]) {
+	topLayer := int(nodeToRemove.level) - 1
 	for {
-		lFound := s.findNodeRemove(value, &preds, &succs)
-		if isMarked || // this process mark this node or we can find this node in the skip list
-			lFound != -1 && succs[lFound].flags.MGet(fullyLinked|marked, fullyLinked) && (int(succs[lFound].level)-1) == lFound {
-			if !isMarked { // we don't mark this node for now
-				nodeToRemove = succs[lFound]
-				topLayer = lFound
-				nodeToRemove.mu.Lock()
-				if nodeToRemove.flags.Get(marked) {
-					// The node is marked by another process,
-					// the physical deletion will be accomplished by another process.
-					nodeToRemove.mu.Unlock()
-					return false
-				}
-				nodeToRemove.flags.SetTrue(marked)
-				isMarked = true
-			}
-			// Accomplish the physical deletion.
-			var (
-				highestLocked        = -1 // the highest level being locked by this process
-				valid                = true
-				pred, succ, prevPred *int64Node
-			)
-			for layer := 0; valid && (layer <= topLayer); layer++ {
-				pred, succ = preds[layer], succs[layer]
-				if pred != prevPred { // the node in this layer could be locked by previous loop
-					pred.mu.Lock()
-					highestLocked = layer
-					prevPred = pred
-				}
-				// valid check if there is another node has inserted into the skip list in this layer
-				// during this process, or the previous is removed by another process.
-				// It is valid if:
-				// 1. the previous node exists.
-				// 2. no another node has inserted into the skip list in this layer.
-				valid = !pred.flags.Get(marked) && pred.loadNext(layer) == succ
-			}
-			if !valid {
-				unlockInt64(preds, highestLocked)
-				continue
+		var (
+			highestLocked        = -1 // the highest level being locked by this process
+			valid                = true
+			pred, succ, prevPred *node[T]
+		)
+		for layer := 0; valid && (layer <= topLayer); layer++ {
+			pred, succ = preds[layer], succs[layer]
+			if pred != prevPred { // the node in this layer could be locked by previous loop
+				pred.mu.Lock()
+				highestLocked = layer
+				prevPred = pred
 			}
-			for i := topLayer; i >= 0; i-- {
-				// Now we own the `nodeToRemove`, no other goroutine will modify it.
-				// So we don't need `nodeToRemove.loadNext`
-				preds[i].atomicStoreNext(i, nodeToRemove.loadNext(i))
+			// valid check if there is another node has inserted into the skip list in this layer
+			// during this process, or the previous is removed by another process.
+			// It is valid if:
+			// 1. the previous node exists.
+			// 2. no another node has inserted into the skip list in this layer.
+			valid = !pred.flags.Get(marked) && pred.loadNext(layer) == succ
+		}
+		if !valid {
+			unlock(*preds, highestLocked)
+			s.findNodeRemove(l, nodeToRemove.value, preds, succs)
+			continue
+		}
+		indexed := s.beginUpdate()
+		if indexed {
+			s.indexRemove(l, nodeToRemove, preds)
+		} else {
+			nodeToRemove.unlink(preds)
+		}
+		s.endUpdate(indexed)
+		nodeToRemove.mu.Unlock()
+		unlock(*preds, highestLocked)
+		atomic.AddInt64(&l.length, -1)
+		return
+	}
+}
+
+// PopMin removes the smallest value from the skip set and returns it, ok is false if the skip set is empty.
+//
+// Each value is returned by exactly one of the concurrent PopMin, PopMax and Remove calls,
+// so the skip set can be used as a concurrent priority queue.
+func (s *Set[T]) PopMin() (value T, ok bool) {
+	return s.pop(func(l *list[T]) *node[T] {
+		return s.findFirstValid(l.header.atomicLoadNext(0))
+	})
+}
+
+// PopMax removes the largest value from the skip set and returns it, ok is false if the skip set is empty.
+//
+// Each value is returned by exactly one of the concurrent PopMin, PopMax and Remove calls.
+func (s *Set[T]) PopMax() (value T, ok bool) {
+	return s.pop(func(l *list[T]) *node[T] {
+		return s.findLastValid(l, func(T) bool { return true })
+	})
+}
+
+// PopMinWait removes the smallest value from the skip set and returns it, if the skip set is empty,
+// it waits until a value is added or the ctx is done. The returned error is ctx.Err() if the ctx
+// is done before a value is popped.
+//
+// All the waiting goroutines are woken up by an Add, they race for the value via PopMin.
+func (s *Set[T]) PopMinWait(ctx context.Context) (value T, err error) {
+	for {
+		if v, ok := s.PopMin(); ok {
+			return v, nil
+		}
+		ch := s.added.register()
+		// Check again, the value may be added before register.
+		v, ok := s.PopMin()
+		if !ok {
+			select {
+			case <-ch:
+			case <-ctx.Done():
+				err = ctx.Err()
 			}
-			nodeToRemove.mu.Unlock()
-			unlockInt64(preds, highestLocked)
-			atomic.AddInt64(&s.length, -1)
-			return true
 		}
-		return false
+		s.added.unregister()
+		if ok {
+			return v, nil
+		}
+		if err != nil {
+			return value, err
+		}
 	}
 }
 
+// pop removes the node returned by find, it retries if another process has marked the node.
+func (s *Set[T]) pop(find func(l *list[T]) *node[T]) (value T, ok bool) {
+	var preds, succs [maxLevel]*node[T]
+	l := s.load()
+	for {
+		nodeToRemove := find(l)
+		if nodeToRemove == nil {
+			return value, false
+		}
+		if !s.mark(l, nodeToRemove) {
+			// The node is removed by another process, the next search will skip it.
+			continue
+		}
+		s.findNodeRemove(l, nodeToRemove.value, &preds, &succs)
+		s.unlink(l, nodeToRemove, &preds, &succs)
+		return nodeToRemove.value, true
+	}
+}
+
+// Clear removes all the values from the skip set atomically, the skip set can be used as a new one
+// after Clear returns, and the calls after it will see an empty skip set.
+//
+// The skip list is replaced with an empty one as a whole, so Clear costs O(1) instead of O(n) and
+// it never waits for other goroutines. The in-progress calls that have loaded the old skip list
+// take effect before Clear, e.g. a concurrent Add may return true while its value is cleared.
+// The readers that are already iterating the skip set, such as Range and Iterator, will not stop
+// and may still see the values in the old skip list.
+func (s *Set[T]) Clear() {
+	s.cur.Store(s.newList())
+	atomic.StoreInt64(&s.highestLevel, defaultHighestLevel)
+}
+
 // Range calls f sequentially for each value present in the skip set.
 // If f returns false, range stops the iteration.
-func (s *Int64Set) Range(f func(value int64) bool) {
-	x := s.header.atomicLoadNext(0)
+func (s *Set[T]) Range(f func(value T) bool) {
+	s.rangeFrom(s.load().header.atomicLoadNext(0), f)
+}
+
+// RangeFrom calls f sequentially for each value present in the skip set,
+// starting from the smallest value greater than or equal to start.
+// If f returns false, range stops the iteration.
+func (s *Set[T]) RangeFrom(start T, f func(value T) bool) {
+	x := s.findLast(s.load(), func(v T) bool { return s.less(v, start) })
+	s.rangeFrom(x.atomicLoadNext(0), f)
+}
+
+// Bounds specifies whether the lower bound lo and the upper bound hi are in a range.
+type Bounds uint8
+
+const (
+	Closed     Bounds = iota // lo <= v <= hi
+	ClosedOpen               // lo <= v < hi
+	OpenClosed               // lo < v <= hi
+	Open                     // lo < v < hi
+)
+
+func (b Bounds) includeLo() bool {
+	return b == Closed || b == ClosedOpen
+}
+
+func (b Bounds) includeHi() bool {
+	return b == Closed || b == OpenClosed
+}
+
+// beforeLo reports whether v is before the range that starts from lo.
+func (s *Set[T]) beforeLo(v, lo T, bounds Bounds) bool {
+	if bounds.includeLo() {
+		return s.less(v, lo)
+	}
+	return !s.less(lo, v)
+}
+
+// afterHi reports whether v is after the range that ends at hi.
+func (s *Set[T]) afterHi(v, hi T, bounds Bounds) bool {
+	if bounds.includeHi() {
+		return s.less(hi, v)
+	}
+	return !s.less(v, hi)
+}
+
+// RangeBetween calls f sequentially for each value v present in the skip set that is between
+// lo and hi, the bounds decide whether lo and hi are included, e.g. Closed means lo <= v <= hi.
+// If f returns false, range stops the iteration.
+func (s *Set[T]) RangeBetween(lo, hi T, bounds Bounds, f func(value T) bool) {
+	x := s.findLast(s.load(), func(v T) bool { return s.beforeLo(v, lo, bounds) })
+	s.rangeFrom(x.atomicLoadNext(0), func(value T) bool {
+		return !s.afterHi(value, hi, bounds) && f(value)
+	})
+}
+
+// RangeReverse calls f sequentially for each value present in the skip set in reverse order.
+// If f returns false, range stops the iteration.
+//
+// The nodes have no backward links, every step searches the predecessor of the previous value,
+// so it costs O(log n) per value instead of O(1) per value as Range does.
+func (s *Set[T]) RangeReverse(f func(value T) bool) {
+	for value, ok := s.Max(); ok; value, ok = s.Lower(value) {
+		if !f(value) {
+			break
+		}
+	}
+}
+
+// RangeReverseFrom calls f sequentially for each value present in the skip set in reverse order,
+// starting from the largest value less than or equal to start.
+// If f returns false, range stops the iteration.
+func (s *Set[T]) RangeReverseFrom(start T, f func(value T) bool) {
+	for value, ok := s.Floor(start); ok; value, ok = s.Lower(value) {
+		if !f(value) {
+			break
+		}
+	}
+}
+
+// rangeFrom calls f sequentially for each fully linked and unmarked node starting from x.
+func (s *Set[T]) rangeFrom(x *node[T], f func(value T) bool) {
 	for x != nil {
-		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+		if !s.present(x) {
 			x = x.atomicLoadNext(0)
 			continue
 		}
@@ -294,6 +652,6 @@ func (s *Int64Set) Range(f func(value int64) bool) {
 }
 
 // Len return the length of this skip set.
-func (s *Int64Set) Len() int {
-	return int(atomic.LoadInt64(&s.length))
+func (s *Set[T]) Len() int {
+	return int(atomic.LoadInt64(&s.load().length))
 }
@@ -37,30 +37,30 @@ func Example() {
 func TestIntSet(t *testing.T) {
 	// Correctness.
 	l := NewInt()
-	if l.length != 0 {
+	if l.load().length != 0 {
 		t.Fatal("invalid length")
 	}
 	if l.Contains(0) {
 		t.Fatal("invalid contains")
 	}
 
-	if !l.Add(0) || l.length != 1 {
+	if !l.Add(0) || l.load().length != 1 {
 		t.Fatal("invalid add")
 	}
 	if !l.Contains(0) {
 		t.Fatal("invalid contains")
 	}
-	if !l.Remove(0) || l.length != 0 {
+	if !l.Remove(0) || l.load().length != 0 {
 		t.Fatal("invalid remove")
 	}
 
-	if !l.Add(20) || l.length != 1 {
+	if !l.Add(20) || l.load().length != 1 {
 		t.Fatal("invalid add")
 	}
-	if !l.Add(22) || l.length != 2 {
+	if !l.Add(22) || l.load().length != 2 {
 		t.Fatal("invalid add")
 	}
-	if !l.Add(21) || l.length != 3 {
+	if !l.Add(21) || l.load().length != 3 {
 		t.Fatal("invalid add")
 	}
 
@@ -79,7 +79,7 @@ func TestIntSet(t *testing.T) {
 		return true
 	})
 
-	if !l.Remove(21) || l.length != 2 {
+	if !l.Remove(21) || l.load().length != 2 {
 		t.Fatal("invalid remove")
 	}
 
@@ -121,8 +121,8 @@ func TestIntSet(t *testing.T) {
 		}()
 	}
 	wg.Wait()
-	if l.length != int64(num) {
-		t.Fatalf("invalid length expected %d, got %d", num, l.length)
+	if l.load().length != int64(num) {
+		t.Fatalf("invalid length expected %d, got %d", num, l.load().length)
 	}
 
 	// Don't contains 0 after concurrent addion.
@@ -157,8 +157,8 @@ func TestIntSet(t *testing.T) {
 		}()
 	}
 	wg.Wait()
-	if l.length != 0 {
-		t.Fatalf("invalid length expected %d, got %d", 0, l.length)
+	if l.load().length != 0 {
+		t.Fatalf("invalid length expected %d, got %d", 0, l.load().length)
 	}
 
 	// Test all methods.
@@ -358,3 +358,389 @@ func TestStringSet(t *testing.T) {
 		}
 	}
 }
+
+func TestSetOrder(t *testing.T) {
+	s := New[float64]()
+	nums := []float64{math.Inf(-1), -1.5, 0, 3, math.Inf(1)}
+	for i := len(nums) - 1; i >= 0; i-- {
+		if !s.Add(nums[i]) {
+			t.Fatal("invalid add")
+		}
+	}
+	// NaN is equal to itself and smaller than all other values.
+	if !s.Add(math.NaN()) || s.Add(math.NaN()) || !s.Contains(math.NaN()) {
+		t.Fatal("invalid NaN")
+	}
+	if !s.Remove(math.NaN()) || s.Contains(math.NaN()) || s.Len() != len(nums) {
+		t.Fatal("invalid NaN")
+	}
+	i := 0
+	s.Range(func(value float64) bool {
+		if nums[i] != value {
+			t.Fatal("invalid range")
+		}
+		i++
+		return true
+	})
+
+	d := NewDesc[string]()
+	for _, v := range []string{"b", "c", "a"} {
+		d.Add(v)
+	}
+	var res string
+	d.Range(func(value string) bool {
+		res += value
+		return true
+	})
+	if res != "cba" {
+		t.Fatal("invalid range", res)
+	}
+}
+
+func TestSetFunc(t *testing.T) {
+	type version struct {
+		major, minor int
+	}
+	s := NewFunc(func(a, b version) bool {
+		if a.major != b.major {
+			return a.major < b.major
+		}
+		return a.minor < b.minor
+	})
+	for _, v := range []version{{1, 2}, {0, 9}, {1, 0}, {1, 2}} {
+		s.Add(v)
+	}
+	if s.Len() != 3 || !s.Contains(version{1, 0}) || s.Contains(version{2, 0}) {
+		t.Fatal("invalid")
+	}
+	expected := []version{{0, 9}, {1, 0}, {1, 2}}
+	i := 0
+	s.Range(func(value version) bool {
+		if expected[i] != value {
+			t.Fatal("invalid range")
+		}
+		i++
+		return true
+	})
+
+	// Concurrent add and remove with a custom order.
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			s.Add(version{i % 10, i})
+			s.Remove(version{i % 10, i - 1})
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	pre := version{-1, -1}
+	s.Range(func(value version) bool {
+		if !(pre.major < value.major || pre.major == value.major && pre.minor < value.minor) {
+			t.Fatal("invalid range")
+		}
+		pre = value
+		return true
+	})
+}
+
+func TestStringSetLex(t *testing.T) {
+	nums := []string{"", "a", "ab", "b", "ba", "z", "\xff"}
+	x, y := NewStringLex(), NewStringLexDesc()
+	for i := len(nums) - 1; i >= 0; i-- {
+		x.Add(nums[i])
+		y.Add(nums[i])
+	}
+	if x.Len() != len(nums) || y.Len() != len(nums) || !x.Contains("") || x.Contains("c") {
+		t.Fatal("invalid")
+	}
+	i := 0
+	x.Range(func(value string) bool {
+		if nums[i] != value {
+			t.Fatal("invalid range")
+		}
+		i++
+		return true
+	})
+	y.Range(func(value string) bool {
+		i--
+		if nums[i] != value {
+			t.Fatal("invalid range")
+		}
+		return true
+	})
+}
+
+func TestRangeBetween(t *testing.T) {
+	s := NewInt()
+	for i := 0; i < 100; i += 3 {
+		s.Add(i)
+	}
+	collect := func(f func(func(value int) bool)) []int {
+		var res []int
+		f(func(value int) bool {
+			res = append(res, value)
+			return true
+		})
+		return res
+	}
+	if res := collect(func(f func(int) bool) { s.RangeFrom(90, f) }); fmt.Sprint(res) != "[90 93 96 99]" {
+		t.Fatal("invalid range", res)
+	}
+	if res := collect(func(f func(int) bool) { s.RangeFrom(91, f) }); fmt.Sprint(res) != "[93 96 99]" {
+		t.Fatal("invalid range", res)
+	}
+	if res := collect(func(f func(int) bool) { s.RangeFrom(100, f) }); len(res) != 0 {
+		t.Fatal("invalid range", res)
+	}
+	if res := collect(func(f func(int) bool) { s.RangeBetween(3, 12, ClosedOpen, f) }); fmt.Sprint(res) != "[3 6 9]" {
+		t.Fatal("invalid range", res)
+	}
+	if res := collect(func(f func(int) bool) { s.RangeBetween(-5, 2, ClosedOpen, f) }); fmt.Sprint(res) != "[0]" {
+		t.Fatal("invalid range", res)
+	}
+	if res := collect(func(f func(int) bool) { s.RangeBetween(4, 5, ClosedOpen, f) }); len(res) != 0 {
+		t.Fatal("invalid range", res)
+	}
+	var n int
+	s.RangeBetween(0, 100, ClosedOpen, func(value int) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Fatal("range doesn't stop")
+	}
+
+	d := NewIntDesc()
+	for i := 0; i < 10; i++ {
+		d.Add(i)
+	}
+	if res := collect(func(f func(int) bool) { d.RangeBetween(7, 3, ClosedOpen, f) }); fmt.Sprint(res) != "[7 6 5 4]" {
+		t.Fatal("invalid range", res)
+	}
+
+	for bounds, expected := range map[Bounds]string{
+		Closed:     "[3 6 9 12]",
+		ClosedOpen: "[3 6 9]",
+		OpenClosed: "[6 9 12]",
+		Open:       "[6 9]",
+	} {
+		if res := collect(func(f func(int) bool) { s.RangeBetween(3, 12, bounds, f) }); fmt.Sprint(res) != expected {
+			t.Fatal("invalid range", bounds, res)
+		}
+	}
+	// The largest value of the type can be included.
+	m := NewInt64()
+	m.Add(math.MaxInt64)
+	m.Add(math.MaxInt64 - 1)
+	var res []int64
+	m.RangeBetween(math.MaxInt64-1, math.MaxInt64, OpenClosed, func(value int64) bool {
+		res = append(res, value)
+		return true
+	})
+	if len(res) != 1 || res[0] != math.MaxInt64 {
+		t.Fatal("invalid range", res)
+	}
+}
+
+func TestRangeReverse(t *testing.T) {
+	s := NewInt()
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+	}
+	i := 99
+	s.RangeReverse(func(value int) bool {
+		if value != i {
+			t.Fatal("invalid range", value)
+		}
+		i--
+		return true
+	})
+	if i != -1 {
+		t.Fatal("invalid range")
+	}
+	var res []int
+	s.RangeReverseFrom(50, func(value int) bool {
+		res = append(res, value)
+		return len(res) < 3
+	})
+	if fmt.Sprint(res) != "[50 49 48]" {
+		t.Fatal("invalid range", res)
+	}
+
+	// Concurrent remove the odd values while ranging.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		for i := 1; i < 100; i += 2 {
+			s.Remove(i)
+		}
+		wg.Done()
+	}()
+	pre := 100
+	s.RangeReverse(func(value int) bool {
+		if value >= pre {
+			t.Fatal("invalid range", value)
+		}
+		pre = value
+		return true
+	})
+	wg.Wait()
+	if pre != 0 {
+		t.Fatal("invalid range")
+	}
+}
+
+func TestPop(t *testing.T) {
+	s := NewInt()
+	if _, ok := s.PopMin(); ok {
+		t.Fatal("invalid pop")
+	}
+	if _, ok := s.PopMax(); ok {
+		t.Fatal("invalid pop")
+	}
+	for _, v := range []int{3, 1, 2} {
+		s.Add(v)
+	}
+	if v, ok := s.PopMin(); !ok || v != 1 || s.Len() != 2 || s.Contains(1) {
+		t.Fatal("invalid pop min", v)
+	}
+	if v, ok := s.PopMax(); !ok || v != 3 || s.Len() != 1 || s.Contains(3) {
+		t.Fatal("invalid pop max", v)
+	}
+
+	// Every value must be popped exactly once.
+	const num = 10000
+	s = NewInt()
+	for i := 0; i < num; i++ {
+		s.Add(i)
+	}
+	var (
+		wg     sync.WaitGroup
+		popped [num]int32
+	)
+	for i := 0; i < 16; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			for {
+				var (
+					v  int
+					ok bool
+				)
+				switch i % 3 {
+				case 0:
+					v, ok = s.PopMin()
+				case 1:
+					v, ok = s.PopMax()
+				default:
+					v = int(fastrand.Uint32n(num))
+					ok = s.Remove(v)
+					if !ok && s.Len() > 0 {
+						continue
+					}
+				}
+				if !ok {
+					break
+				}
+				atomic.AddInt32(&popped[v], 1)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	for i, n := range popped {
+		if n != 1 {
+			t.Fatalf("%d is popped %d times", i, n)
+		}
+	}
+	if s.Len() != 0 {
+		t.Fatal("invalid length")
+	}
+}
+
+func TestClear(t *testing.T) {
+	s := NewIndexed[int64]()
+	s.Clear()
+	for i := int64(0); i < 1000; i++ {
+		s.Add(i)
+	}
+	// The ongoing range finishes on the detached nodes.
+	var count int
+	s.Range(func(value int64) bool {
+		if value == 500 {
+			s.Clear()
+		}
+		count++
+		return true
+	})
+	if count != 1000 || s.Len() != 0 || s.Contains(0) {
+		t.Fatal("invalid clear", count)
+	}
+	if _, ok := s.Min(); ok {
+		t.Fatal("invalid clear")
+	}
+	// The skip set can be used again, and the index is reset.
+	for i := int64(0); i < 100; i++ {
+		s.Add(i)
+	}
+	checkRank(t, s)
+	s.Clear()
+	if s.Rank(100) != 0 || s.CountRange(0, 100, ClosedOpen) != 0 {
+		t.Fatal("invalid rank after clear")
+	}
+	if atomic.LoadInt64(&s.highestLevel) != defaultHighestLevel {
+		t.Fatal("invalid highest level after clear")
+	}
+	s.Add(5)
+	checkRank(t, s)
+
+	// The length always matches the values after concurrent modifications.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			for j := 0; j < 10000; j++ {
+				v := int64(fastrand.Uint32n(1000))
+				switch {
+				case i == 0 && j%1000 == 0:
+					s.Clear()
+				case i == 1 && j%100 == 0:
+					s.AddBatch([]int64{v, v + 1, v + 2})
+				case i%2 == 0:
+					s.Remove(v)
+				default:
+					s.Add(v)
+				}
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+	var n int
+	s.Range(func(int64) bool {
+		n++
+		return true
+	})
+	if n != s.Len() {
+		t.Fatal("invalid length", n, s.Len())
+	}
+	checkRank(t, s)
+
+	ss := NewSum[int64]()
+	ss.Add(1)
+	ss.Add(2)
+	ss.Clear()
+	ss.Add(3)
+	if sum := ss.SumRange(0, 10, ClosedOpen); sum != 3 {
+		t.Fatal("invalid sum after clear", sum)
+	}
+
+	str := NewString()
+	str.Add("a")
+	str.Clear()
+	if str.Len() != 0 || str.Contains("a") {
+		t.Fatal("invalid clear")
+	}
+}
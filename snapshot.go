@@ -0,0 +1,251 @@
+package skipset
+
+import (
+	"iter"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// version records the timestamps of the addition and the removal of a node, 0 if not set yet,
+// see stamp. The nodes are versioned only after the skip list is snapshotted for the first time,
+// so the skip sets without snapshots never pay for the timestamps.
+type version struct {
+	added, removed uint64
+}
+
+// enableVersions versions the new nodes of the skip list, it returns after all the nodes added
+// before are fully linked and all the nodes removed before are unlinked.
+//
+// The flag is read by the adders with their preds locked and by the removers with their node
+// locked, so the flag is seen by all the writers that lock a node after the walk below passes it,
+// and the walk waits for the writers that have locked a node before it.
+func (l *list[T]) enableVersions() {
+	l.versioning.Do(func() {
+		atomic.StoreUint32(&l.versioned, 1)
+		for x := l.header; x != nil; x = x.atomicLoadNext(0) {
+			x.mu.Lock()
+			x.mu.Unlock() // only waits for the writers holding the lock
+		}
+	})
+}
+
+// snapshotList records the nodes removed during the in-progress snapshots of a skip list.
+type snapshotList[T any] struct {
+	active int32 // the number of the in-progress snapshots, accessed atomically
+	mu     sync.Mutex
+	states []*snapshotState[T]
+}
+
+type snapshotState[T any] struct {
+	ts   uint64     // the timestamp of the snapshot
+	dead []*node[T] // the nodes removed during the snapshot, some of them may not be in it
+}
+
+func (l *snapshotList[T]) register(st *snapshotState[T]) {
+	l.mu.Lock()
+	l.states = append(l.states, st)
+	atomic.AddInt32(&l.active, 1)
+	l.mu.Unlock()
+}
+
+// unregister removes st from the list and returns the nodes recorded for it.
+func (l *snapshotList[T]) unregister(st *snapshotState[T]) []*node[T] {
+	l.mu.Lock()
+	l.states = slices.DeleteFunc(l.states, func(x *snapshotState[T]) bool { return x == st })
+	atomic.AddInt32(&l.active, -1)
+	l.mu.Unlock()
+	return st.dead
+}
+
+// bury records the node being removed for all the in-progress snapshots, the timestamp of
+// a snapshot may not be taken yet, so the snapshots check whether the node is in them later.
+func (l *snapshotList[T]) bury(n *node[T]) {
+	l.mu.Lock()
+	for _, st := range l.states {
+		st.dead = append(st.dead, n)
+	}
+	l.mu.Unlock()
+}
+
+// Snapshot returns a read-only view of the skip set as of one instant during the call, it is
+// not affected by the later modifications of the skip set.
+//
+// Every node is stamped with the clock of the skip set when it is added and removed (see stamp),
+// and taking a snapshot only increases the clock, so it never blocks or waits for the writers,
+// except that the first snapshot of the skip set waits for the in-progress writers once, see
+// version. Then the level 0 is walked and the nodes added before the snapshot and not removed
+// before it are collected. The nodes that are removed and unlinked during the walk are recorded
+// by the removers, so none of them is missed.
+func (s *Set[T]) Snapshot() *Snapshot[T] {
+	var (
+		l  *list[T]
+		st *snapshotState[T]
+	)
+	for {
+		l, st = s.load(), new(snapshotState[T])
+		// Register before taking the timestamp, so the nodes removed after it are recorded.
+		l.snapshots.register(st)
+		l.enableVersions()
+		st.ts = atomic.AddUint64(&s.clock, 1) - 1
+		if s.load() == l {
+			break
+		}
+		// The skip set is cleared, the snapshot of the old skip list would have the cleared values.
+		l.snapshots.unregister(st)
+	}
+
+	var values []T
+	for x := l.header.atomicLoadNext(0); x != nil; x = x.atomicLoadNext(0) {
+		if s.isVisible(st, x) {
+			values = append(values, x.value)
+		}
+	}
+	var merged bool
+	for _, n := range l.snapshots.unregister(st) {
+		if s.isVisible(st, n) {
+			// The removed node may have been collected by the walk.
+			values = append(values, n.value)
+			merged = true
+		}
+	}
+	if merged {
+		slices.SortFunc(values, func(a, b T) int {
+			if s.less(a, b) {
+				return -1
+			}
+			if s.less(b, a) {
+				return 1
+			}
+			return 0
+		})
+		values = slices.CompactFunc(values, func(a, b T) bool {
+			return !s.less(a, b) && !s.less(b, a)
+		})
+	}
+	return &Snapshot[T]{values: slices.Clip(values), less: s.less, ordered: s.ordered}
+}
+
+// isVisible reports whether the node is in the skip set at the timestamp of the snapshot,
+// i.e. it is added at or before the timestamp and not removed at or before it. The node is
+// stamped if it is fully linked, then the timestamp taken later is after the snapshot.
+func (s *Set[T]) isVisible(st *snapshotState[T], n *node[T]) bool {
+	if !n.flags.Get(fullyLinked) {
+		// The node will be stamped as added after the snapshot.
+		return false
+	}
+	// Load the version after the flag, a node is versioned before it is marked.
+	removed := n.flags.Get(marked)
+	v := n.ver.Load()
+	if v == nil {
+		// The node is added before the list is versioned, and it is removed before that if it is
+		// marked, see enableVersions.
+		return !removed
+	}
+	if s.stamp(&v.added) > st.ts {
+		return false
+	}
+	return !n.flags.Get(marked) || s.stamp(&v.removed) > st.ts
+}
+
+// Clone returns a new skip set with the same order and values as the skip set,
+// the values are taken from a Snapshot. The new skip set is indexed if the skip set is.
+func (s *Set[T]) Clone() *Set[T] {
+	c := s.Snapshot().Clone()
+	if s.index.indexed {
+		c.agg = s.agg
+		c.buildIndex()
+	}
+	return c
+}
+
+// Snapshot is a read-only view of a skip set, see Set.Snapshot.
+// The values are stored in a sorted slice, so it is safe for concurrent use.
+type Snapshot[T any] struct {
+	values  []T
+	less    func(a, b T) bool
+	ordered searcher[T] // for the clones, see Clone
+}
+
+// search returns the index of the first value that is not less than value.
+func (s *Snapshot[T]) search(value T) int {
+	return sort.Search(len(s.values), func(i int) bool {
+		return !s.less(s.values[i], value)
+	})
+}
+
+// Len return the length of this snapshot.
+func (s *Snapshot[T]) Len() int {
+	return len(s.values)
+}
+
+// Contains check if the value is in the snapshot.
+func (s *Snapshot[T]) Contains(value T) bool {
+	i := s.search(value)
+	return i < len(s.values) && !s.less(value, s.values[i])
+}
+
+// Min returns the smallest value in the snapshot, ok is false if the snapshot is empty.
+func (s *Snapshot[T]) Min() (value T, ok bool) {
+	return s.at(0)
+}
+
+// Max returns the largest value in the snapshot, ok is false if the snapshot is empty.
+func (s *Snapshot[T]) Max() (value T, ok bool) {
+	return s.at(len(s.values) - 1)
+}
+
+// Ceiling returns the smallest value greater than or equal to the given value,
+// ok is false if there is no such value.
+func (s *Snapshot[T]) Ceiling(value T) (T, bool) {
+	return s.at(s.search(value))
+}
+
+// Floor returns the largest value less than or equal to the given value,
+// ok is false if there is no such value.
+func (s *Snapshot[T]) Floor(value T) (T, bool) {
+	i := s.search(value)
+	if i < len(s.values) && !s.less(value, s.values[i]) {
+		return s.values[i], true
+	}
+	return s.at(i - 1)
+}
+
+// Rank returns the number of values in the snapshot that are less than the given value.
+func (s *Snapshot[T]) Rank(value T) int {
+	return s.search(value)
+}
+
+// Select returns the value at index k (0-based) of the snapshot, ok is false if k is out of range.
+func (s *Snapshot[T]) Select(k int) (value T, ok bool) {
+	return s.at(k)
+}
+
+func (s *Snapshot[T]) at(i int) (value T, ok bool) {
+	if i < 0 || i >= len(s.values) {
+		return value, false
+	}
+	return s.values[i], true
+}
+
+// Range calls f sequentially for each value in the snapshot.
+// If f returns false, range stops the iteration.
+func (s *Snapshot[T]) Range(f func(value T) bool) {
+	for _, v := range s.values {
+		if !f(v) {
+			break
+		}
+	}
+}
+
+// All returns an iterator over the values in the snapshot.
+func (s *Snapshot[T]) All() iter.Seq[T] {
+	return slices.Values(s.values)
+}
+
+// Clone returns a new skip set with the same order and values as the snapshot.
+func (s *Snapshot[T]) Clone() *Set[T] {
+	c, _ := buildSorted(newSet(s.less, s.ordered), s.values) // the values are always sorted
+	return c
+}
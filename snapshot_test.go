@@ -0,0 +1,231 @@
+package skipset
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	s := NewInt64()
+	snap := s.Snapshot()
+	if snap.Len() != 0 || snap.Contains(0) {
+		t.Fatal("invalid empty snapshot")
+	}
+	if _, ok := snap.Min(); ok {
+		t.Fatal("invalid empty snapshot")
+	}
+	for i := int64(0); i < 100; i += 2 {
+		s.Add(i)
+	}
+	snap = s.Snapshot()
+	s.Add(1)
+	s.Remove(0)
+	if snap.Len() != 50 || !snap.Contains(0) || snap.Contains(1) || snap.Contains(3) {
+		t.Fatal("invalid snapshot")
+	}
+	if v, ok := snap.Min(); !ok || v != 0 {
+		t.Fatal("invalid min", v)
+	}
+	if v, ok := snap.Max(); !ok || v != 98 {
+		t.Fatal("invalid max", v)
+	}
+	if v, ok := snap.Ceiling(3); !ok || v != 4 {
+		t.Fatal("invalid ceiling", v)
+	}
+	if v, ok := snap.Floor(3); !ok || v != 2 {
+		t.Fatal("invalid floor", v)
+	}
+	if v, ok := snap.Floor(4); !ok || v != 4 {
+		t.Fatal("invalid floor", v)
+	}
+	if _, ok := snap.Floor(-1); ok {
+		t.Fatal("invalid floor")
+	}
+	if _, ok := snap.Ceiling(99); ok {
+		t.Fatal("invalid ceiling")
+	}
+	if snap.Rank(10) != 5 {
+		t.Fatal("invalid rank")
+	}
+	if v, ok := snap.Select(5); !ok || v != 10 {
+		t.Fatal("invalid select", v)
+	}
+	var n int
+	snap.Range(func(int64) bool {
+		n++
+		return n < 10
+	})
+	if n != 10 {
+		t.Fatal("invalid range", n)
+	}
+
+	// The clones are independent of the original one.
+	c := snap.Clone()
+	c.Add(1000)
+	if c.Len() != 51 || s.Contains(1000) || !c.Contains(0) {
+		t.Fatal("invalid clone")
+	}
+	c = s.Clone()
+	if !slices.Equal(slices.Collect(c.All()), slices.Collect(s.All())) {
+		t.Fatal("invalid clone")
+	}
+	checkRank(t, c)
+
+	ds := NewIntDesc()
+	ds.Add(1)
+	ds.Add(2)
+	if !slices.Equal(slices.Collect(ds.Clone().All()), []int{2, 1}) {
+		t.Fatal("invalid desc clone")
+	}
+
+	ss := NewSum[int]()
+	ss.Add(1)
+	ss.Add(2)
+	sc := ss.Clone()
+	ss.Add(3)
+	if sum := sc.SumRange(0, 10, ClosedOpen); sum != 3 {
+		t.Fatal("invalid sum clone", sum)
+	}
+
+	str := NewString()
+	str.Add("a")
+	strc := str.Clone()
+	strs := str.Snapshot()
+	str.Add("b")
+	if strc.Len() != 1 || !strc.Contains("a") || strc.Contains("b") {
+		t.Fatal("invalid string clone")
+	}
+	if strs.Len() != 1 || !strs.Contains("a") || strs.Contains("b") {
+		t.Fatal("invalid string snapshot")
+	}
+	if !slices.Equal(slices.Collect(strs.All()), []string{"a"}) || strs.Clone().Len() != 1 {
+		t.Fatal("invalid string snapshot")
+	}
+}
+
+func TestSnapshotVersion(t *testing.T) {
+	s := NewInt64()
+	for i := int64(0); i < 10; i++ {
+		s.Add(i)
+	}
+	s.Remove(9)
+	// The nodes are not versioned until the skip set is snapshotted.
+	versioned := func(v int64) bool {
+		var preds, succs [maxLevel]*node[int64]
+		l := s.load()
+		lFound := s.findNodeRemove(l, v, &preds, &succs)
+		return lFound != -1 && succs[lFound].ver.Load() != nil
+	}
+	for i := int64(0); i < 9; i++ {
+		if versioned(i) {
+			t.Fatal("invalid version", i)
+		}
+	}
+	snap := s.Snapshot()
+	s.Add(10)
+	s.Remove(0)
+	if !versioned(10) || versioned(1) {
+		t.Fatal("invalid version")
+	}
+	if snap.Len() != 9 || !snap.Contains(0) || snap.Contains(10) {
+		t.Fatal("invalid snapshot")
+	}
+	snap = s.Snapshot()
+	if snap.Len() != 9 || snap.Contains(0) || !snap.Contains(10) {
+		t.Fatal("invalid snapshot")
+	}
+	// A cleared skip set starts without versions.
+	s.Clear()
+	s.Add(1)
+	if versioned(1) || s.Snapshot().Len() != 1 {
+		t.Fatal("invalid version after clear")
+	}
+}
+
+func TestSnapshotConsistency(t *testing.T) {
+	const (
+		writers = 8
+		space   = 1 << 16
+		step    = 1 << 8
+	)
+	s := NewInt64()
+	// The fillers make the walk between two values of a writer take a while.
+	for i := int64(0); i < writers*space; i += 16 {
+		s.Add(i)
+	}
+	// Each writer moves its value downward by adding the next value before removing the current
+	// one, so it always has one or two values in the skip set. A walk without a consistent view
+	// may miss both of them.
+	for g := int64(0); g < writers; g++ {
+		s.Add(g*space + space - 1)
+	}
+	var (
+		wg   sync.WaitGroup
+		stop atomic.Bool
+	)
+	for g := int64(0); g < writers; g++ {
+		wg.Add(1)
+		go func(g int64) {
+			for v := g*space + space - 1; !stop.Load(); {
+				next := v - step
+				if next < g*space {
+					next = g*space + space - 1
+				}
+				s.Add(next)
+				s.Remove(v)
+				v = next
+			}
+			wg.Done()
+		}(g)
+	}
+	for i := 0; i < 30; i++ {
+		var counts [writers]int
+		s.Snapshot().Range(func(v int64) bool {
+			if v%16 != 0 {
+				counts[v/space]++
+			}
+			return true
+		})
+		for g, n := range counts {
+			if n != 1 && n != 2 {
+				t.Fatalf("inconsistent snapshot of writer %d: %d values", g, n)
+			}
+		}
+	}
+	stop.Store(true)
+	wg.Wait()
+}
+
+func TestSnapshotClear(t *testing.T) {
+	const n = 100
+	s := NewInt64()
+	var (
+		wg   sync.WaitGroup
+		stop atomic.Bool
+	)
+	wg.Add(1)
+	go func() {
+		// The skip set always contains a prefix of [0, n).
+		for !stop.Load() {
+			s.Clear()
+			for i := int64(0); i < n; i++ {
+				s.Add(i)
+			}
+		}
+		wg.Done()
+	}()
+	for i := 0; i < 1000; i++ {
+		var expected int64
+		s.Snapshot().Range(func(v int64) bool {
+			if v != expected {
+				t.Fatalf("inconsistent snapshot: expected %d, got %d", expected, v)
+			}
+			expected++
+			return true
+		})
+	}
+	stop.Store(true)
+	wg.Wait()
+}
@@ -0,0 +1,94 @@
+package skipset
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// aggregator maintains the sums of the values in the index of a skip set.
+type aggregator[T any] struct {
+	add func(a, b T) T
+}
+
+// SumSet is a numeric skip set that also maintains the sum of the values in every span of the
+// index, so that the sum of the values in a range can be computed in O(log n).
+//
+// The SumSet is always indexed, so the writers are slower than a Set under contention, see NewIndexed.
+// The sums are computed in type T and only from the values in the spans, they are never restored by
+// subtraction, so a value that is removed or out of a range never affects the sum of the range. The
+// values are added in a different grouping from adding them one by one, so a float sum may differ in
+// the last bits, and a sum may overflow in the same way. The minimum and maximum of a range need
+// no aggregates because the values are sorted, see Ceiling and Lower.
+type SumSet[T Number] struct {
+	*Set[T]
+}
+
+// NewSum return an empty skip set in ascending order which supports SumRange and AvgRange.
+func NewSum[T Number]() *SumSet[T] {
+	s := New[T]()
+	s.agg = &aggregator[T]{
+		add: func(a, b T) T { return a + b },
+	}
+	s.buildIndex()
+	return &SumSet[T]{Set: s}
+}
+
+// Clone returns a new SumSet with the same values as the skip set, see Set.Clone.
+func (s *SumSet[T]) Clone() *SumSet[T] {
+	return &SumSet[T]{Set: s.Set.Clone()}
+}
+
+// rangeSum returns the number and the sum of the values between lo and hi.
+//
+// It searches the last node before the range, then moves forward via the highest span of the
+// current node that ends in the range, so only the sums of the spans inside the range are added.
+func (s *SumSet[T]) rangeSum(lo, hi T, bounds Bounds) (count int64, sum T) {
+	// The sums are not accessed atomically, so block the writers instead of reading optimistically.
+	s.index.mu.Lock()
+	defer s.index.mu.Unlock()
+	x := s.load().header
+	for i := maxLevel - 1; i >= 0; i-- {
+		nex := x.loadNext(i)
+		for nex != nil && s.beforeLo(nex.value, lo, bounds) {
+			x = nex
+			nex = x.loadNext(i)
+		}
+	}
+	for {
+		i := int(x.level) - 1
+		for ; i >= 0; i-- {
+			if nex := x.loadNext(i); nex != nil && !s.afterHi(nex.value, hi, bounds) {
+				break
+			}
+		}
+		if i < 0 {
+			return count, sum
+		}
+		count += x.loadSpan(i)
+		sum += x.indexAt(i).sum
+		x = x.loadNext(i)
+	}
+}
+
+// SumRange returns the sum of the values in the skip set between lo and hi, the bounds decide
+// whether lo and hi are included, see RangeBetween.
+//
+// It costs O(log n) and blocks the writers for the time of the walk, the result reflects
+// the skip set at an instant during the call, a value that is being removed concurrently may
+// still be counted.
+func (s *SumSet[T]) SumRange(lo, hi T, bounds Bounds) T {
+	_, sum := s.rangeSum(lo, hi, bounds)
+	return sum
+}
+
+// AvgRange returns the average of the values in the skip set between lo and hi, ok is false
+// if there is no such value. See SumRange for the bounds, the cost and the consistency.
+func (s *SumSet[T]) AvgRange(lo, hi T, bounds Bounds) (avg float64, ok bool) {
+	count, sum := s.rangeSum(lo, hi, bounds)
+	if count == 0 {
+		return 0, false
+	}
+	return float64(sum) / float64(count), true
+}
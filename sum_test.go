@@ -0,0 +1,147 @@
+package skipset
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestSumSet(t *testing.T) {
+	s := NewSum[int64]()
+	if s.SumRange(0, 100, ClosedOpen) != 0 {
+		t.Fatal("invalid sum")
+	}
+	if _, ok := s.AvgRange(0, 100, ClosedOpen); ok {
+		t.Fatal("invalid avg")
+	}
+	for i := int64(1); i <= 100; i++ {
+		s.Add(i)
+	}
+	if sum := s.SumRange(1, 101, ClosedOpen); sum != 5050 {
+		t.Fatal("invalid sum", sum)
+	}
+	if sum := s.SumRange(10, 20, ClosedOpen); sum != 145 {
+		t.Fatal("invalid sum", sum)
+	}
+	if sum := s.SumRange(20, 10, ClosedOpen); sum != 0 {
+		t.Fatal("invalid sum", sum)
+	}
+	if avg, ok := s.AvgRange(1, 11, ClosedOpen); !ok || avg != 5.5 {
+		t.Fatal("invalid avg", avg)
+	}
+	for bounds, expected := range map[Bounds]int64{Closed: 165, ClosedOpen: 145, OpenClosed: 155, Open: 135} {
+		if sum := s.SumRange(10, 20, bounds); sum != expected {
+			t.Fatal("invalid sum", bounds, sum)
+		}
+	}
+	if sum := s.SumRange(100, 100, Closed); sum != 100 {
+		t.Fatal("invalid sum", sum)
+	}
+	// The order statistics share the same index.
+	if s.Rank(50) != 49 || s.CountRange(10, 20, ClosedOpen) != 10 {
+		t.Fatal("invalid rank")
+	}
+
+	// Check the sums after concurrent modifications.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			for j := 0; j < 2000; j++ {
+				v := int64(fastrand.Uint32n(1000))
+				switch fastrand.Uint32n(3) {
+				case 0:
+					s.Add(v)
+				case 1:
+					s.Remove(v)
+				default:
+					s.SumRange(v, v+100, ClosedOpen)
+				}
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 100; i++ {
+		lo, hi := int64(fastrand.Uint32n(1000)), int64(fastrand.Uint32n(1000))
+		var expected int64
+		s.RangeBetween(lo, hi, ClosedOpen, func(value int64) bool {
+			expected += value
+			return true
+		})
+		if sum := s.SumRange(lo, hi, ClosedOpen); sum != expected {
+			t.Fatalf("invalid sum of [%d, %d): expected %d, got %d", lo, hi, expected, sum)
+		}
+	}
+	checkRank(t, s.Set)
+
+	f := NewSum[float64]()
+	for _, v := range []float64{0.5, 1.5, 2.5} {
+		f.Add(v)
+	}
+	if avg, _ := f.AvgRange(0, 3, ClosedOpen); avg != 1.5 {
+		t.Fatal("invalid avg", avg)
+	}
+}
+
+func TestSumSetFloat(t *testing.T) {
+	s := NewSum[float64]()
+	for i := 0; i < 100; i++ {
+		s.Add(float64(i))
+	}
+	// The removed values never leave a residue in the sums.
+	for _, v := range []float64{math.Inf(1), math.Inf(-1), 1e17, -1e17, math.MaxFloat64, 0.1} {
+		s.Add(v)
+		s.Remove(v)
+		if sum := s.SumRange(0, 100, ClosedOpen); sum != 4950 {
+			t.Fatal("invalid sum after removing", v, sum)
+		}
+	}
+	s.Add(math.Inf(1))
+	s.Add(math.Inf(-1))
+	if sum := s.SumRange(math.Inf(-1), math.Inf(1), Closed); !math.IsNaN(sum) {
+		t.Fatal("invalid sum", sum)
+	}
+	if sum := s.SumRange(math.Inf(-1), 10, ClosedOpen); !math.IsInf(sum, -1) {
+		t.Fatal("invalid sum", sum)
+	}
+	// The infinities out of a range never affect the sum of the range.
+	if sum := s.SumRange(10, 20, ClosedOpen); sum != 145 {
+		t.Fatal("invalid sum", sum)
+	}
+	if avg, ok := s.AvgRange(0, 100, Open); !ok || avg != 50 {
+		t.Fatal("invalid avg", avg)
+	}
+	s.Remove(math.Inf(-1))
+	if sum := s.SumRange(math.Inf(-1), math.Inf(1), ClosedOpen); sum != 4950 {
+		t.Fatal("invalid sum", sum)
+	}
+	s.Remove(math.Inf(1))
+
+	// A large value added and removed among many others does not cancel the small ones.
+	for i := 0; i < 10000; i++ {
+		v := float64(fastrand.Uint32n(1000)) / 2
+		if fastrand.Uint32n(2) == 0 {
+			s.Add(v)
+		} else {
+			s.Remove(v)
+		}
+		if i%10 == 0 {
+			s.Add(1e17 + float64(i))
+			s.Remove(1e17 + float64(i))
+		}
+	}
+	for i := 0; i < 100; i++ {
+		lo, hi := float64(fastrand.Uint32n(500)), float64(fastrand.Uint32n(500))
+		var expected float64
+		s.RangeBetween(lo, hi, Closed, func(value float64) bool {
+			expected += value
+			return true
+		})
+		if sum := s.SumRange(lo, hi, Closed); sum != expected {
+			t.Fatalf("invalid sum of [%v, %v]: expected %v, got %v", lo, hi, expected, sum)
+		}
+	}
+}
@@ -1,8 +1,6 @@
 package skipset
 
 import (
-	_ "unsafe" // for linkname
-
 	"github.com/zhangyunhao116/fastrand"
 	"github.com/zhangyunhao116/wyhash"
 )
@@ -13,9 +11,6 @@ const (
 	defaultHighestLevel = 3
 )
 
-//go:linkname cmpstring runtime.cmpstring
-func cmpstring(a, b string) int
-
 func hash(s string) uint64 {
 	return wyhash.Sum64String(s)
 }